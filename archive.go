@@ -0,0 +1,17 @@
+package paperboy
+
+import (
+	"context"
+	"time"
+)
+
+// ArchiveService stores the articles as fetched from the news sources, apart from their
+// summaries, so that they may be summarized again without being fetched.
+//
+//	Archive: stores the articles fetched by the job, replacing earlier versions.
+//	Walk: calls fn with each of the job's articles published between from and to, oldest
+//		first, stopping at the first error.
+type ArchiveService interface {
+	Archive(ctx context.Context, job string, res []*Result) error
+	Walk(ctx context.Context, job string, from, to time.Time, fn func(*Result) error) error
+}
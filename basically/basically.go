@@ -0,0 +1,79 @@
+package basically
+
+import (
+	"context"
+	"fmt"
+	"paperboy-back"
+
+	"github.com/algao1/basically/btrank"
+	"github.com/algao1/basically/document"
+	"github.com/algao1/basically/parser"
+	"github.com/algao1/basically/trank"
+)
+
+// Summarizer is an implementation of paperboy.Summarizer using basically.
+type Summarizer struct {
+	parser *parser.Parser
+}
+
+var _ paperboy.Summarizer = (*Summarizer)(nil)
+
+// Create initializes the summarizer with a parser.
+func Create() *Summarizer {
+	return &Summarizer{parser: parser.Create()}
+}
+
+// Summarize returns the summary sentences and keywords of the text, using
+// biased TextRank for summarization and TextRank for keyword extraction.
+// As basically cannot be interrupted, ctx is only checked between steps.
+func (s *Summarizer) Summarize(ctx context.Context, text string, opts paperboy.SummarizeOptions) (*paperboy.Article, error) {
+	if opts.Sentences <= 0 {
+		opts.Sentences = paperboy.DefaultSummarizeOptions.Sentences
+	}
+	if opts.Keywords <= 0 {
+		opts.Keywords = paperboy.DefaultSummarizeOptions.Keywords
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Set up basically document.
+	doc, err := document.Create(text, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, s.parser)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to create basically document", err)
+	}
+
+	// Summarization using basically.
+	sents, err := doc.Summarize(opts.Sentences, 0, "")
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to summarize document", err)
+	}
+	psents := make([]*paperboy.Sentence, len(sents))
+	for idx, sen := range sents {
+		psents[idx] = &paperboy.Sentence{Sentence: sen.Raw, Sentiment: sen.Sentiment}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Keyword extraction using basically.
+	kwords, err := doc.Highlight(opts.Keywords, true)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to extract keywords", err)
+	}
+	pkwords := make([]*paperboy.Keyword, len(kwords))
+	for idx, w := range kwords {
+		pkwords[idx] = &paperboy.Keyword{Word: w.Word, Weight: w.Weight}
+	}
+
+	fLength, sLength := doc.Characters()
+
+	return &paperboy.Article{
+		SummaryText: psents,
+		Keywords:    pkwords,
+		FullLength:  fLength,
+		SummLength:  sLength,
+	}, nil
+}
@@ -0,0 +1,208 @@
+package chi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"paperboy-back"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// requireToken rejects requests without the bearer token.
+func requireToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(got, []byte("Bearer "+token)) != 1 {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// task is the state of a Tasker, as served by the admin routes. Leading is whether the
+// replica serving the request runs the task, only then may it be paused, resumed, or triggered.
+type task struct {
+	Name    string
+	State   paperboy.TaskState
+	Circuit paperboy.CircuitState
+	Leading bool
+	Next    time.Time
+	Runs    []*paperboy.Run `json:",omitempty"`
+}
+
+// Closure to bind TaskerFactory and RunService to the HandlerFunc in order to serve the state
+// of the tasks, and their last runs.
+func adminGetTasks(tf paperboy.TaskerFactory, rs paperboy.RunService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtain the query parameter 'runs'.
+		sruns := r.URL.Query().Get("runs")
+		n, err := strconv.Atoi(sruns)
+		if err != nil {
+			log.Printf("[%s] query param 'runs=%s' is invalid\n", r.URL, sruns)
+			n = 10
+		}
+
+		taskers := tf.Taskers()
+		tasks := make([]task, len(taskers))
+		for i, t := range taskers {
+			tasks[i] = task{Name: t.Name(), State: t.State(), Circuit: t.Circuit(), Leading: t.Leading(), Next: t.Next()}
+			if rs == nil || n <= 0 {
+				continue
+			}
+
+			tasks[i].Runs, err = rs.Runs(r.Context(), t.Name(), n)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		js, err := json.Marshal(tasks)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Sets and writes content-type of 'application/json'.
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// Closure to bind TaskerFactory to the HandlerFunc in order to pause, resume, trigger, or stop a task.
+// Actions apply to the replica serving the request, which answers 409 Conflict if another leads the task.
+// A stopped task stays stopped on that replica until it restarts, as there is no action to start it
+// again. Another replica may take over the task in the meantime, as the stopped one gives up its lock.
+func adminTaskAction(tf paperboy.TaskerFactory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, action := chi.URLParam(r, "name"), chi.URLParam(r, "action")
+
+		t, err := tf.Tasker(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		switch action {
+		case "pause":
+			err = t.Pause()
+		case "resume":
+			err = t.Resume()
+		case "trigger":
+			err = t.Trigger()
+		case "stop":
+			// Give a run in progress some time to finish, before cancelling it.
+			ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+			defer cancel()
+			err = t.Stop(ctx)
+		default:
+			http.Error(w, "unknown action "+action, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, paperboy.ErrTaskerStopped) || errors.Is(err, paperboy.ErrNotLeader) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("[%s] %s task %q\n", r.URL, action, name)
+
+		js, err := json.Marshal(task{Name: t.Name(), State: t.State(), Circuit: t.Circuit(), Leading: t.Leading(), Next: t.Next()})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Sets and writes content-type of 'application/json'.
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// Closure to bind DeadLetterService to the HandlerFunc in order to serve dead letters.
+func adminGetDeadLetters(dls paperboy.DeadLetterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtain the query parameter 'size'.
+		ssize := r.URL.Query().Get("size")
+		size, err := strconv.Atoi(ssize)
+		if err != nil {
+			log.Printf("[%s] query param 'size=%s' is invalid\n", r.URL, ssize)
+			size = 50
+		}
+
+		deadLetters, err := dls.DeadLetters(r.Context(), size)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		js, err := json.Marshal(deadLetters)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Sets and writes content-type of 'application/json'.
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// Closure to bind DeadLetterService to the HandlerFunc in order to discard a dead letter.
+func adminDiscardDeadLetter(dls paperboy.DeadLetterService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtain the query parameter 'id'.
+		id := r.URL.Query().Get("id")
+
+		if err := dls.Discard(r.Context(), id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Printf("[%s] discarded dead letter %q\n", r.URL, id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Closure to bind DeadLetterRetrier to the HandlerFunc in order to retry a dead letter.
+func adminRetryDeadLetter(dlr paperboy.DeadLetterRetrier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtain the query parameter 'id'.
+		id := r.URL.Query().Get("id")
+
+		if dlr == nil {
+			http.Error(w, "retries are not supported", http.StatusNotImplemented)
+			return
+		}
+		if err := dlr.Retry(r.Context(), id); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		log.Printf("[%s] retried dead letter %q\n", r.URL, id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Closure to bind Reloader to the HandlerFunc in order to reload the configuration.
+func adminReload(rl paperboy.Reloader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := rl.Reload(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		log.Printf("[%s] reloaded config\n", r.URL)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -19,8 +19,26 @@ type Handler struct {
 
 var _ http.Handler = (*Handler)(nil)
 
+// Services contains the services used by the handlers.
+type Services struct {
+	SummaryService  paperboy.SummaryService
+	RevisionService paperboy.RevisionService
+	TaskerFactory   paperboy.TaskerFactory
+	RunService      paperboy.RunService
+	StoryService    paperboy.StoryService
+
+	DeadLetterService paperboy.DeadLetterService
+	DeadLetterRetrier paperboy.DeadLetterRetrier
+	Reloader          paperboy.Reloader
+
+	// AdminToken is the bearer token required by the admin routes,
+	// which are not served if it is empty.
+	AdminToken string
+}
+
 // Init configures and returns a chi router.
-func Init(ss paperboy.SummaryService) *Handler {
+func Init(s Services) *Handler {
+	ss, rs := s.SummaryService, s.RevisionService
 	r := chi.NewRouter()
 
 	// Middleware.
@@ -29,8 +47,30 @@ func Init(ss paperboy.SummaryService) *Handler {
 
 	// RESTy routes for 'summaries' resource.
 	r.Get("/api/summary", apiGetSummary(ss))
+	r.Get("/api/summary/{id}/revisions", apiGetRevisions(rs))
 	r.Get("/api/summaries", apiSearchSummaries(ss))
 	r.Get("/api/summaries/{section}", apiGetSummaries(ss))
+	if s.StoryService != nil {
+		r.Get("/api/stories", apiGetStories(s.StoryService))
+	}
+
+	// Routes to manage the ingestion tasks.
+	if s.AdminToken != "" {
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(requireToken(s.AdminToken))
+			r.Get("/tasks", adminGetTasks(s.TaskerFactory, s.RunService))
+			r.Post("/tasks/{name}/{action}", adminTaskAction(s.TaskerFactory))
+
+			if s.Reloader != nil {
+				r.Post("/reload", adminReload(s.Reloader))
+			}
+			if s.DeadLetterService != nil {
+				r.Get("/deadletters", adminGetDeadLetters(s.DeadLetterService))
+				r.Delete("/deadletters", adminDiscardDeadLetter(s.DeadLetterService))
+				r.Post("/deadletters/retry", adminRetryDeadLetter(s.DeadLetterRetrier))
+			}
+		})
+	}
 
 	return &Handler{chi: r}
 }
@@ -54,7 +94,7 @@ func apiSearchSummaries(ss paperboy.SummaryService) http.HandlerFunc {
 			size = 10
 		}
 
-		summaries, err := ss.Search(query, size)
+		summaries, err := ss.Search(r.Context(), query, size)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -97,7 +137,7 @@ func apiGetSummaries(ss paperboy.SummaryService) http.HandlerFunc {
 		}
 
 		// Fetch summaries using SummaryService.
-		summaries, last, err := ss.Summaries(section, endDate, size)
+		summaries, last, err := ss.Summaries(r.Context(), section, endDate, size)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
@@ -122,7 +162,7 @@ func apiGetSummary(ss paperboy.SummaryService) http.HandlerFunc {
 		// Obtain the query parameter 'id'.
 		id := r.URL.Query().Get("id")
 
-		summary, err := ss.Summary(id)
+		summary, err := ss.Summary(r.Context(), id)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
@@ -139,3 +179,68 @@ func apiGetSummary(ss paperboy.SummaryService) http.HandlerFunc {
 		w.Write(js)
 	}
 }
+
+// Closure to bind RevisionService to the HandlerFunc in order to serve revisions.
+func apiGetRevisions(rs paperboy.RevisionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+
+		revisions, err := rs.Revisions(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Printf("[%s] found %d revisions", r.URL, len(revisions))
+
+		js, err := json.Marshal(revisions)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Sets and writes content-type of 'application/json'.
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
+
+// Closure to bind StoryService to the HandlerFunc in order to serve stories.
+func apiGetStories(sts paperboy.StoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtain the query parameter 'section'.
+		section := r.URL.Query().Get("section")
+
+		// Obtain the query parameter 'size'.
+		ssize := r.URL.Query().Get("size")
+		size, err := strconv.Atoi(ssize)
+		if err != nil {
+			log.Printf("[%s] query param 'size=%s' is invalid\n", r.URL, ssize)
+			size = 10
+		}
+
+		// Obtain the query parameter 'min', the fewest summaries in a story.
+		smin := r.URL.Query().Get("min")
+		min, err := strconv.Atoi(smin)
+		if err != nil {
+			log.Printf("[%s] query param 'min=%s' is invalid\n", r.URL, smin)
+			min = 2
+		}
+
+		stories, err := sts.Stories(r.Context(), section, min, size)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Printf("[%s] found %d stories", r.URL, len(stories))
+
+		js, err := json.Marshal(stories)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Sets and writes content-type of 'application/json'.
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(js)
+	}
+}
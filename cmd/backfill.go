@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"paperboy-back"
+	"paperboy-back/news/guardian"
+	"syscall"
+	"time"
+)
+
+// backfill fetches, summarizes, and stores a section's articles over a historical range.
+//
+//	paperboy-back backfill --section world --from 2026-01-01 --to 2026-02-01
+func backfill(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	path := fs.String("config", os.Getenv("CONFIG_PATH"), "path to the YAML config of the jobs")
+	source := fs.String("source", "guardian", "name of the news source")
+	section := fs.String("section", "", "section to backfill, such as 'world'")
+	from := fs.String("from", "", "start of the range, as 2006-01-02 or RFC 3339")
+	to := fs.String("to", time.Now().UTC().Format(time.RFC3339), "end of the range, as 2006-01-02 or RFC 3339")
+	window := fs.Duration("window", 24*time.Hour, "length of the range fetched at a time")
+	rate := fs.Duration("rate", 1*time.Second, "minimum time between Guardian API requests")
+	pageSize := fs.Int("page-size", 200, "number of articles per page")
+	maxPages := fs.Int("max-pages", 50, "maximum number of pages per window")
+	fs.Parse(args)
+
+	if *section == "" {
+		return fmt.Errorf("backfill: --section is required")
+	}
+	start, err := parseDate(*from)
+	if err != nil {
+		return fmt.Errorf("backfill: invalid --from: %w", err)
+	}
+	end, err := parseDate(*to)
+	if err != nil {
+		return fmt.Errorf("backfill: invalid --to: %w", err)
+	}
+
+	// Use the configured job for its filters and options, if there is one.
+	loaded := loadConfig(*path)
+	conf := paperboy.Config{Workers: loaded.Workers, Jobs: []paperboy.Job{{Source: *source, Section: *section}}}
+	for _, job := range loaded.Jobs {
+		if job.Source == *source && job.Section == *section {
+			conf.Jobs[0] = job
+			break
+		}
+	}
+	conf.Jobs[0].PageSize = *pageSize
+	conf.Jobs[0].MaxPages = *maxPages
+	conf.SetDefaults()
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+
+	// Stop backfilling on SIGINT and SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serv, closer := newServer(ctx, &conf, *path)
+	defer closer()
+	for _, ns := range serv.NewsSources {
+		if gs, ok := ns.(*guardian.Service); ok {
+			gs.Interval = *rate
+		}
+	}
+
+	log.Printf("backfilling %s from %s to %s\n", conf.Jobs[0].Name, start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if err := serv.Backfill(ctx, conf.Jobs[0], start, end, *window); err != nil {
+		return err
+	}
+	log.Println("backfill complete")
+	return nil
+}
+
+// parseDate parses a date given as either 2006-01-02 or RFC 3339.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
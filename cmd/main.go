@@ -1,24 +1,158 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"paperboy-back"
+	"paperboy-back/basically"
 	"paperboy-back/chi"
 	"paperboy-back/core"
 	"paperboy-back/mongo"
 	"paperboy-back/news/guardian"
+	"paperboy-back/news/rss"
+	"paperboy-back/news/scraper"
+	"paperboy-back/redis"
 	"paperboy-back/tasker"
+	"paperboy-back/yaml"
+	"strconv"
+	"syscall"
+	"time"
+
+	// Embeds the time zone database, for the schedules of the jobs.
+	_ "time/tzdata"
 )
 
 func main() {
-	// Initialize services, factories, and handlers.
-	ss, err := mongo.Open(
+	var cmd string
+	if len(os.Args) > 1 {
+		cmd = os.Args[1]
+	}
+
+	var err error
+	switch cmd {
+	case "backfill":
+		err = backfill(os.Args[2:])
+	case "reprocess":
+		err = reprocess(os.Args[2:])
+	default:
+		err = serve(os.Args[1:])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serve runs the server, and the ingestion jobs.
+func serve(args []string) error {
+	fs := flag.NewFlagSet("paperboy-back", flag.ExitOnError)
+	path := fs.String("config", os.Getenv("CONFIG_PATH"), "path to the YAML config of the jobs")
+	fs.Parse(args)
+
+	// Shut down gracefully on SIGINT and SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serv, closer := newServer(ctx, loadConfig(*path), *path)
+	defer closer()
+
+	// Reload the filter rules on SIGHUP.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		rl := &reloader{path: *path, serv: serv}
+		for {
+			select {
+			case <-hup:
+				if err := rl.Reload(ctx); err != nil {
+					log.Println(err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Println("running on port 8080")
+
+	return serv.Run(ctx, 8080)
+}
+
+// disconnect closes the connections to the database, and to Redis if lk is set.
+func disconnect(db *mongo.DB, lk *redis.Locker) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.Close(ctx); err != nil {
+		log.Println(err)
+	}
+	if lk != nil {
+		if err := lk.Close(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// loadConfig returns the config at path, or an empty config if no path is given,
+// so that the defaults are used.
+func loadConfig(path string) *paperboy.Config {
+	if path == "" {
+		return &paperboy.Config{}
+	}
+
+	conf, err := yaml.Load(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("loaded %d jobs from %s\n", len(conf.Jobs), path)
+
+	return conf
+}
+
+// reloader reloads the filter rules of the jobs from the config at path.
+type reloader struct {
+	path string
+	serv *core.Server
+}
+
+// Reload replaces the filter rules of the jobs with those in the config.
+func (rl *reloader) Reload(ctx context.Context) error {
+	if rl.path == "" {
+		return fmt.Errorf("unable to reload: no config path given")
+	}
+
+	conf, err := yaml.Load(rl.path)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to reload", err)
+	}
+	if err := rl.serv.SetRules(conf); err != nil {
+		return fmt.Errorf("%q: %w", "unable to reload", err)
+	}
+	log.Printf("reloaded rules of %d jobs from %s\n", len(conf.Jobs), rl.path)
+	return nil
+}
+
+// newServer initializes services, factories, and handlers, and injects them into a server.
+// The config at path may be reloaded at runtime. The returned function closes the connections.
+func newServer(ctx context.Context, conf *paperboy.Config, path string) (*core.Server, func()) {
+	db, err := mongo.Connect(
+		ctx,
 		os.Getenv("MONGO_URI"),
 		os.Getenv("MONGO_KEY"),
 	)
 	if err != nil {
 		log.Fatal(err)
 	}
+	ss := mongo.NewSummaryService(db)
+	ms := mongo.NewMarkService(db)
+	rns := mongo.NewRunService(db)
+	dls := mongo.NewDeadLetterService(db)
+	as := mongo.NewArchiveService(db)
+	sts := mongo.NewStoryService(db)
 
 	// TODO: should really fix this in the future.
 
@@ -33,20 +167,47 @@ func main() {
 	// 	cdb, ss)
 
 	gs := guardian.Create(os.Getenv("GUARDIAN_KEY"))
-	tf := &tasker.Factory{}
-	h := chi.Init(ss)
+	rs := rss.Create()
+	ws := scraper.Create()
+	sz := basically.Create()
+	tf := &tasker.Factory{RunService: rns}
 
-	// Dependency injection.
-	serv := core.Server{
-		SummaryService:  ss,
-		GuardianService: gs,
-		TaskerFactory:   tf,
-		Handler:         h,
+	// Runs each job on one replica at a time, if Redis is configured.
+	var lk *redis.Locker
+	if addr := os.Getenv("CACHE_URL"); addr != "" {
+		cdb, _ := strconv.Atoi(os.Getenv("CACHE_DB"))
+		lk, err = redis.NewLocker(addr, os.Getenv("CACHE_PORT"), os.Getenv("CACHE_PASS"), cdb)
+		if err != nil {
+			log.Fatal(err)
+		}
+		tf.Locker = lk
 	}
 
-	log.Println("running on port 8080")
-
-	if err = serv.Run(8080); err != nil {
-		log.Fatal(err)
+	// Dependency injection.
+	serv := &core.Server{
+		SummaryService:    ss,
+		NewsSources:       []paperboy.NewsSource{gs, rs, ws},
+		Summarizer:        sz,
+		MarkService:       ms,
+		ArchiveService:    as,
+		TaskerFactory:     tf,
+		DeadLetterService: dls,
+		StoryService:      sts,
+		Jobs:              conf.Jobs,
+		Workers:           conf.Workers,
 	}
+
+	// The server retries dead letters, and has its rules reloaded, for the handler.
+	serv.Handler = chi.Init(chi.Services{
+		SummaryService:    ss,
+		RevisionService:   ss,
+		TaskerFactory:     tf,
+		RunService:        rns,
+		StoryService:      sts,
+		DeadLetterService: dls,
+		DeadLetterRetrier: serv,
+		Reloader:          &reloader{path: path, serv: serv},
+		AdminToken:        os.Getenv("ADMIN_TOKEN"),
+	})
+	return serv, func() { disconnect(db, lk) }
 }
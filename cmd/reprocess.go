@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"paperboy-back"
+	"paperboy-back/core"
+	"syscall"
+	"time"
+)
+
+// reprocess summarizes the archived articles of the jobs again, with the current settings.
+//
+//	paperboy-back reprocess --job "Guardian World" --from 2026-01-01
+func reprocess(args []string) error {
+	fs := flag.NewFlagSet("reprocess", flag.ExitOnError)
+	path := fs.String("config", os.Getenv("CONFIG_PATH"), "path to the YAML config of the jobs")
+	name := fs.String("job", "", "name of the job to reprocess, defaults to every job")
+	from := fs.String("from", "2000-01-01", "start of the range, as 2006-01-02 or RFC 3339")
+	to := fs.String("to", time.Now().UTC().Format(time.RFC3339), "end of the range, as 2006-01-02 or RFC 3339")
+	fs.Parse(args)
+
+	start, err := parseDate(*from)
+	if err != nil {
+		return fmt.Errorf("reprocess: invalid --from: %w", err)
+	}
+	end, err := parseDate(*to)
+	if err != nil {
+		return fmt.Errorf("reprocess: invalid --to: %w", err)
+	}
+
+	// Use the configured jobs, for their current options.
+	conf := loadConfig(*path)
+	if conf.Jobs == nil {
+		conf.Jobs = append(conf.Jobs, core.DefaultJobs...)
+	}
+	conf.SetDefaults()
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+
+	jobs := make([]paperboy.Job, 0, len(conf.Jobs))
+	for _, job := range conf.Jobs {
+		if *name == "" || job.Name == *name {
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		return fmt.Errorf("reprocess: job %q not found", *name)
+	}
+
+	// Stop reprocessing on SIGINT and SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serv, closer := newServer(ctx, conf, *path)
+	defer closer()
+
+	for _, job := range jobs {
+		log.Printf("reprocessing %s from %s to %s\n", job.Name, start.Format(time.RFC3339), end.Format(time.RFC3339))
+		if err := serv.Reprocess(ctx, job, start, end); err != nil {
+			return err
+		}
+	}
+	log.Println("reprocess complete")
+	return nil
+}
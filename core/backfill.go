@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"paperboy-back"
+	"strings"
+	"time"
+)
+
+// Retries of a failed backfill window, the delay doubling after each attempt.
+const (
+	backfillAttempts = 3
+	backfillDelay    = 10 * time.Second
+)
+
+// Backfill fetches, summarizes, and stores the job's articles published between from and to.
+// The range is split into windows, so that each window fits within the job's page cap, and
+// progress is logged after each window. A failed window is retried with backoff, then
+// skipped, and the windows which failed are returned in the error, so that they may be
+// backfilled again. High-water marks are left untouched.
+func (s *Server) Backfill(ctx context.Context, job paperboy.Job, from, to time.Time, window time.Duration) error {
+	ns, err := s.source(job.Source)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to backfill", err)
+	}
+	if !from.Before(to) || window <= 0 {
+		return fmt.Errorf("unable to backfill: invalid range %v to %v, with window %v", from, to, window)
+	}
+
+	start := time.Now()
+	windows := int(math.Ceil(float64(to.Sub(from)) / float64(window)))
+	fetched, summarized := 0, 0
+	failed := make([]string, 0)
+
+	for idx, wfrom := 1, from; wfrom.Before(to); idx, wfrom = idx+1, wfrom.Add(window) {
+		wto := wfrom.Add(window)
+		if wto.After(to) {
+			wto = to
+		}
+		span := fmt.Sprintf("--from %s --to %s", wfrom.Format(time.RFC3339), wto.Format(time.RFC3339))
+
+		q := paperboy.Query{
+			Section:  job.Section,
+			From:     wfrom,
+			To:       wto,
+			PageSize: job.PageSize,
+			MaxPages: job.MaxPages,
+			Params:   job.Params,
+		}
+
+		rep, err := s.backfillWindow(ctx, job, ns, q)
+		if ctx.Err() != nil {
+			if len(failed) > 0 {
+				log.Printf("[Backfill %s] %d windows failed before the interruption:\n\t%s\n",
+					job.Name, len(failed), strings.Join(failed, "\n\t"))
+			}
+			return fmt.Errorf("backfill interrupted, resume with --from %s: %w", wfrom.Format(time.RFC3339), ctx.Err())
+		}
+		if err != nil {
+			log.Printf("[Backfill %s] %d/%d %s failed, skipping: %v\n", job.Name, idx, windows, span, err)
+			failed = append(failed, span)
+			continue
+		}
+		fetched += len(rep.fetched)
+		summarized += rep.summarized
+
+		log.Printf("[Backfill %s] %d/%d (%.0f%%) %s to %s: summarized %d of %d articles (%d failed), %d of %d in total, %v elapsed\n",
+			job.Name,
+			idx, windows, 100*float64(idx)/float64(windows),
+			wfrom.Format("2006-01-02 15:04"), wto.Format("2006-01-02 15:04"),
+			rep.summarized, len(rep.fetched), len(rep.failures),
+			summarized, fetched,
+			time.Since(start).Round(time.Second),
+		)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("backfill failed for %d of %d windows, backfill them again with:\n\t%s",
+			len(failed), windows, strings.Join(failed, "\n\t"))
+	}
+	return nil
+}
+
+// backfillWindow ingests the window, retrying with backoff until backfillAttempts have failed.
+func (s *Server) backfillWindow(ctx context.Context, job paperboy.Job, ns paperboy.NewsSource, q paperboy.Query) (*report, error) {
+	delay := backfillDelay
+	for attempt := 1; ; attempt++ {
+		rep, err := s.ingest(ctx, job, ns, q)
+		if err == nil || attempt == backfillAttempts || ctx.Err() != nil {
+			return rep, err
+		}
+		log.Printf("[Backfill %s] attempt %d failed, retrying in %v: %v\n", job.Name, attempt, delay, err)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+}
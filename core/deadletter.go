@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"paperboy-back"
+	"time"
+)
+
+// Defaults of the dead letter retries.
+const (
+	DefaultDeadLetterPeriod   = 6 * time.Hour
+	DefaultDeadLetterAttempts = 5
+)
+
+// deadLetter stores the article which failed to be summarized, if the server has a DeadLetterService.
+func (s *Server) deadLetter(ctx context.Context, job paperboy.Job, r *paperboy.Result, err error) {
+	if s.DeadLetterService == nil {
+		return
+	}
+
+	dl := &paperboy.DeadLetter{
+		ID:     r.ContentID,
+		Job:    job.Name,
+		Result: *r,
+		Stage:  paperboy.StageSummarize,
+		Error:  err.Error(),
+	}
+	if err := s.DeadLetterService.Add(ctx, dl); err != nil {
+		log.Printf("[%s] %v\n", r.ContentID, err)
+	}
+}
+
+// Retry summarizes and stores the dead letter with the given id again, with the options of
+// its job, and discards it once stored. If it fails again, the attempt is counted.
+func (s *Server) Retry(ctx context.Context, id string) error {
+	if s.DeadLetterService == nil {
+		return fmt.Errorf("unable to retry: no dead letter service")
+	}
+
+	dl, err := s.DeadLetterService.DeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var opts paperboy.SummarizeOptions
+	for _, job := range s.jobs {
+		if job.Name == dl.Job {
+			opts = job.Options
+		}
+	}
+
+	free, err := s.worker(ctx)
+	if err != nil {
+		return err
+	}
+	summ, err := extract(ctx, s.Summarizer, &dl.Result, opts)
+	free()
+	if err != nil {
+		dl.Error = err.Error()
+		if aerr := s.DeadLetterService.Attempt(ctx, dl); aerr != nil {
+			log.Printf("[%s] %v\n", id, aerr)
+		}
+		return fmt.Errorf("retry of %q failed: %w", id, err)
+	}
+
+	if err := s.SummaryService.Create(ctx, summ); err != nil {
+		return fmt.Errorf("retry of %q failed: %w", id, err)
+	}
+	s.cluster(ctx, summ)
+	return s.DeadLetterService.Discard(ctx, id)
+}
+
+// DeadLetters returns a Tasker that will periodically retry the dead letters which have been
+// attempted fewer than DeadLetterAttempts times.
+func (s *Server) DeadLetters() (paperboy.Tasker, error) {
+	period := s.DeadLetterPeriod
+	if period == 0 {
+		period = DefaultDeadLetterPeriod
+	}
+	attempts := s.DeadLetterAttempts
+	if attempts == 0 {
+		attempts = DefaultDeadLetterAttempts
+	}
+
+	task := func(ctx context.Context) error {
+		dls, err := s.DeadLetterService.Retryable(ctx, attempts, 100)
+		if err != nil {
+			return err
+		}
+
+		retried, recovered := 0, 0
+		for _, dl := range dls {
+			retried++
+			if err := s.Retry(ctx, dl.ID); err != nil {
+				log.Println(err)
+				continue
+			}
+			recovered++
+		}
+		if retried > 0 {
+			log.Printf("[Dead Letters] recovered %d of %d articles\n", recovered, retried)
+		}
+		if r := paperboy.RunFromContext(ctx); r != nil {
+			r.Fetched, r.Summarized, r.Stored = retried, recovered, recovered
+			r.Failed = retried - recovered
+		}
+		return ctx.Err()
+	}
+
+	conf := paperboy.TaskConfig{Name: "Dead Letters", Period: period, RecoverPeriod: period}
+	t, err := s.TaskerFactory.CreateTasker(conf, task)
+	if err != nil {
+		return t, fmt.Errorf("%q: %w", "could not create dead letter tasker", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,354 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"paperboy-back"
+	"paperboy-back/news"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// DefaultJobs are the jobs run when none are configured.
+var DefaultJobs = []paperboy.Job{
+	{Source: "guardian", Section: "world", Rules: defaultRules},
+	{Source: "guardian", Section: "environment", Rules: defaultRules},
+	{Source: "guardian", Section: "technology", Rules: defaultRules},
+	{Source: "guardian", Section: "science", Rules: defaultRules},
+}
+
+// defaultRules exclude letters to the editor.
+var defaultRules = []paperboy.Rule{{Action: paperboy.RuleExclude, Title: `\| Letters`}}
+
+// filter returns the job's articles which pass its filter, and counts the others in the report.
+func (s *Server) filter(job paperboy.Job, res []*paperboy.Result, rep *report) ([]*paperboy.Result, error) {
+	f, err := s.jobFilter(job)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]*paperboy.Result, 0, len(res))
+	for _, r := range res {
+		if !f.Keep(r) {
+			rep.filtered++
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept, nil
+}
+
+// jobFilter returns the job's filter, as last set by SetRules, or compiled from its rules.
+func (s *Server) jobFilter(job paperboy.Job) (*news.Filter, error) {
+	s.filtersMu.RLock()
+	f, ok := s.filters[job.Name]
+	s.filtersMu.RUnlock()
+	if ok {
+		return f, nil
+	}
+
+	f, err := news.NewFilter(job.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "invalid rules for "+job.Name, err)
+	}
+
+	s.filtersMu.Lock()
+	defer s.filtersMu.Unlock()
+	if s.filters == nil {
+		s.filters = make(map[string]*news.Filter)
+	}
+	if cur, ok := s.filters[job.Name]; ok {
+		return cur, nil
+	}
+	s.filters[job.Name] = f
+	return f, nil
+}
+
+// SetRules replaces the filter rules of the configured jobs at runtime, such as after the
+// config has been edited. Either every job's rules are replaced, or none are.
+func (s *Server) SetRules(conf *paperboy.Config) error {
+	filters := make(map[string]*news.Filter, len(conf.Jobs))
+	for _, job := range conf.Jobs {
+		f, err := news.NewFilter(job.Rules)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "invalid rules for "+job.Name, err)
+		}
+		filters[job.Name] = f
+	}
+
+	s.filtersMu.Lock()
+	defer s.filtersMu.Unlock()
+	if s.filters == nil {
+		s.filters = make(map[string]*news.Filter)
+	}
+	for name, f := range filters {
+		s.filters[name] = f
+	}
+	return nil
+}
+
+// outcome is the summary of an article, or the error summarizing it.
+type outcome struct {
+	res  *paperboy.Result
+	summ *paperboy.Summary
+	err  error
+}
+
+// summarize summarizes the articles on the server's workers, as they become free, and sends
+// an outcome for each. The channel is closed once every started summarization has finished,
+// or no more are started once ctx is done, so it must be drained.
+func (s *Server) summarize(ctx context.Context, job paperboy.Job, res []*paperboy.Result, out chan<- outcome) {
+	var wg sync.WaitGroup
+	defer close(out)
+	defer wg.Wait()
+
+	for _, r := range res {
+		free, err := s.worker(ctx)
+		if err != nil {
+			return
+		}
+
+		wg.Add(1)
+		go func(r *paperboy.Result) {
+			defer wg.Done()
+
+			// The worker is freed before the outcome is sent, so that it is not held
+			// while the summaries before it are stored.
+			summ, err := extract(ctx, s.Summarizer, r, job.Options)
+			free()
+			out <- outcome{res: r, summ: summ, err: err}
+		}(r)
+	}
+}
+
+// extract summarizes the article, and recovers from a panic in the summarizer as an error,
+// since it runs outside of the Tasker's goroutine.
+func extract(ctx context.Context, sz paperboy.Summarizer, r *paperboy.Result, opts paperboy.SummarizeOptions) (summ *paperboy.Summary, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("[%s] summarizer panicked: %v\n%s", r.ContentID, p, debug.Stack())
+			err = fmt.Errorf("summarizer panicked on %q: %v", r.ContentID, p)
+		}
+	}()
+	return news.Extract(ctx, sz, r, opts)
+}
+
+// changed updates the metadata of the articles whose body text is unchanged since they were
+// last summarized, and returns the remaining articles, which need to be summarized.
+func (s *Server) changed(ctx context.Context, res []*paperboy.Result) ([]*paperboy.Result, error) {
+	ids := make([]string, len(res))
+	for idx, r := range res {
+		ids[idx] = r.ContentID
+	}
+
+	hashes, err := s.SummaryService.Hashes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]*paperboy.Result, 0, len(res))
+	for _, r := range res {
+		if hash, ok := hashes[r.ContentID]; !ok || hash != news.Hash(r) {
+			changed = append(changed, r)
+			continue
+		}
+
+		summ, err := news.Metadata(r)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if err := s.SummaryService.UpdateInfo(ctx, summ); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return changed, nil
+}
+
+// from returns the date to fetch the job's articles from. This is the job's high-water mark
+// if it has one, so that a run after downtime covers exactly the gap, and otherwise the
+// start of the lookback window.
+func (s *Server) from(ctx context.Context, job paperboy.Job) (time.Time, error) {
+	from := time.Now().UTC().Add(-job.Lookback)
+	if s.MarkService == nil {
+		return from, nil
+	}
+
+	mark, err := s.MarkService.Mark(ctx, job.Name)
+	if err != nil {
+		return from, err
+	}
+	if !mark.IsZero() {
+		from = mark
+	}
+	return from, nil
+}
+
+// latest returns the latest publication date amongst the results.
+func latest(res []*paperboy.Result) time.Time {
+	var last time.Time
+	for _, r := range res {
+		date, err := time.Parse(news.DateLayout, r.Date)
+		if err == nil && date.After(last) {
+			last = date
+		}
+	}
+	return last
+}
+
+// report contains the outcome of an ingestion, and the articles which failed.
+type report struct {
+	fetched    []*paperboy.Result
+	filtered   int
+	summarized int
+	stored     int
+	failures   []paperboy.Failure
+}
+
+// fail records the failure of the article at the given stage.
+func (rep *report) fail(r *paperboy.Result, stage string, err error) {
+	log.Printf("[%s] failed to %s article: %v\n", r.ContentID, stage, err)
+	rep.failures = append(rep.failures, paperboy.Failure{
+		ContentID: r.ContentID,
+		Title:     r.Title,
+		Stage:     stage,
+		Error:     err.Error(),
+	})
+}
+
+// process summarizes the articles on the workers, and stores the summaries as they arrive.
+// Articles which fail are recorded in the report.
+func (s *Server) process(ctx context.Context, job paperboy.Job, res []*paperboy.Result, rep *report) {
+	out := make(chan outcome)
+	go s.summarize(ctx, job, res, out)
+
+	for o := range out {
+		if o.err != nil {
+			rep.fail(o.res, paperboy.StageSummarize, o.err)
+			s.deadLetter(ctx, job, o.res, o.err)
+			continue
+		}
+		rep.summarized++
+
+		if err := s.SummaryService.Create(ctx, o.summ); err != nil {
+			rep.fail(o.res, paperboy.StageStore, err)
+			continue
+		}
+		rep.stored++
+		s.cluster(ctx, o.summ)
+	}
+}
+
+// ingest fetches the articles matching the query from the news source, then summarizes
+// and stores the articles which are new or have changed. Articles which fail are recorded
+// in the report, and do not prevent the others from being stored.
+func (s *Server) ingest(ctx context.Context, job paperboy.Job, ns paperboy.NewsSource, q paperboy.Query) (*report, error) {
+	res, err := ns.Fetch(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "could not fetch from "+ns.Name(), err)
+	}
+	rep := &report{fetched: res}
+
+	// Keep the articles as fetched, so that they may be summarized again.
+	if s.ArchiveService != nil {
+		if err := s.ArchiveService.Archive(ctx, job.Name, res); err != nil {
+			log.Printf("[%s] %v\n", job.Name, err)
+		}
+	}
+
+	kept, err := s.filter(job, res, rep)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only summarize articles which are new, or have changed.
+	changed, err := s.changed(ctx, kept)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "could not compare article hashes", err)
+	}
+
+	s.process(ctx, job, changed, rep)
+
+	// Articles were left unsummarized. Failed articles do not fail the run, as they are in
+	// the report, so that a malformed article does not send the job into backoff.
+	if err := ctx.Err(); err != nil {
+		return rep, fmt.Errorf("%q: %w", "ingestion was cancelled", err)
+	}
+	return rep, nil
+}
+
+// News returns a Tasker that will periodically fetch news for the job from the news source,
+// and summarize it using the summarizer. If the server has a MarkService, each run only
+// fetches the articles published since the last successful run.
+func (s *Server) News(job paperboy.Job, ns paperboy.NewsSource) (paperboy.Tasker, error) {
+	// Defines the task.
+	task := func(ctx context.Context) error {
+		start := time.Now()
+		from, err := s.from(ctx, job)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "could not find high-water mark", err)
+		}
+
+		q := paperboy.Query{
+			Section:  job.Section,
+			From:     from,
+			PageSize: job.PageSize,
+			MaxPages: job.MaxPages,
+			Params:   job.Params,
+		}
+
+		rep, err := s.ingest(ctx, job, ns, q)
+		if r := paperboy.RunFromContext(ctx); r != nil && rep != nil {
+			r.Fetched = len(rep.fetched)
+			r.Filtered = rep.filtered
+			r.Summarized = rep.summarized
+			r.Stored = rep.stored
+			r.Failed = len(rep.failures)
+			r.Failures = rep.failures
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("[%s] summarized %d of %d articles, with %d filtered and %d failures, in %v",
+			job.Name,
+			rep.summarized,
+			len(rep.fetched),
+			rep.filtered,
+			len(rep.failures),
+			time.Since(start),
+		)
+
+		// Only advance the mark once every summary has been stored. Articles which could not
+		// be summarized are skipped, as they would otherwise hold back the section.
+		if s.MarkService != nil && rep.stored == rep.summarized && len(rep.fetched) > 0 {
+			if err := s.MarkService.SetMark(ctx, job.Name, latest(rep.fetched)); err != nil {
+				return fmt.Errorf("%q: %w", "could not update high-water mark", err)
+			}
+		}
+		return nil
+	}
+
+	// Configures and returns a Tasker.
+	loc, err := time.LoadLocation(job.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "could not create news tasker", err)
+	}
+
+	conf := paperboy.TaskConfig{
+		Name:          job.Name,
+		Period:        job.Period,
+		Schedule:      job.Schedule,
+		Location:      loc,
+		RecoverPeriod: job.RecoverPeriod,
+		Backoff:       job.Backoff.Backoff(),
+	}
+	news, err := s.TaskerFactory.CreateTasker(conf, task)
+	if err != nil {
+		return news, fmt.Errorf("%q: %w", "could not create news tasker", err)
+	}
+
+	return news, nil
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"paperboy-back"
+	"time"
+)
+
+// Number of archived articles summarized at a time while reprocessing.
+const reprocessBatch = 100
+
+// Reprocess summarizes and stores the job's archived articles published between from and to
+// again, with the current summarizer and options, without fetching them. Summaries are
+// regenerated even if the articles are unchanged, and progress is logged after each batch.
+func (s *Server) Reprocess(ctx context.Context, job paperboy.Job, from, to time.Time) error {
+	if s.ArchiveService == nil {
+		return fmt.Errorf("unable to reprocess: no archive service")
+	}
+
+	start := time.Now()
+	rep := &report{}
+	total := 0
+
+	batch := make([]*paperboy.Result, 0, reprocessBatch)
+	flush := func() error {
+		kept, err := s.filter(job, batch, rep)
+		if err != nil {
+			return err
+		}
+		s.process(ctx, job, kept, rep)
+		total += len(batch)
+		batch = make([]*paperboy.Result, 0, reprocessBatch)
+
+		log.Printf("[Reprocess %s] summarized %d of %d articles (%d filtered, %d failed), %v elapsed\n",
+			job.Name, rep.summarized, total, rep.filtered, len(rep.failures), time.Since(start).Round(time.Second))
+		return nil
+	}
+
+	err := s.ArchiveService.Walk(ctx, job.Name, from, to, func(r *paperboy.Result) error {
+		batch = append(batch, r)
+		if len(batch) == reprocessBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to reprocess", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%q: %w", "reprocessing was cancelled", err)
+	}
+
+	return nil
+}
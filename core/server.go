@@ -1,46 +1,179 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"paperboy-back"
+	"paperboy-back/news"
+	"runtime"
+	"sync"
+	"time"
 )
 
 // Server contains all the dependencies required for the application.
 type Server struct {
-	SummaryService  paperboy.SummaryService
-	GuardianService paperboy.GuardianService
-	TaskerFactory   paperboy.TaskerFactory
-	Handler         http.Handler
+	SummaryService paperboy.SummaryService
+	NewsSources    []paperboy.NewsSource
+	Summarizer     paperboy.Summarizer
+	MarkService    paperboy.MarkService
+	ArchiveService paperboy.ArchiveService
+	TaskerFactory  paperboy.TaskerFactory
+	Handler        http.Handler
+
+	// DeadLetterService, if set, keeps the articles which fail to be summarized. They are
+	// retried every DeadLetterPeriod, until attempted DeadLetterAttempts times.
+	DeadLetterService  paperboy.DeadLetterService
+	DeadLetterPeriod   time.Duration
+	DeadLetterAttempts int
+
+	// StoryService, if set, groups the stored summaries into stories, comparing each with
+	// the summaries published within StoryWindow of it.
+	StoryService paperboy.StoryService
+	StoryWindow  time.Duration
+	storiesMu    sync.Mutex
+
+	// Jobs are the ingestion jobs to run, defaults to DefaultJobs.
+	Jobs []paperboy.Job
+	jobs []paperboy.Job
+
+	// Workers is the number of articles summarized at once, shared by all jobs so that
+	// concurrent runs do not oversubscribe the CPU, defaults to GOMAXPROCS.
+	Workers int
+	workers chan struct{}
+	once    sync.Once
+
+	// filters are the compiled rules of the jobs, replaced by SetRules.
+	filters   map[string]*news.Filter
+	filtersMu sync.RWMutex
+
+	// ShutdownTimeout is how long to wait for requests and runs to finish when
+	// shutting down, defaults to DefaultShutdownTimeout.
+	ShutdownTimeout time.Duration
 }
 
-// Run starts the server at the designated port.
-func (s *Server) Run(port int) error {
-	// Start the tasks.
-	gworld, err := GuardianNews("world", -2, s.SummaryService, s.GuardianService, s.TaskerFactory)
-	if err != nil {
-		return fmt.Errorf("%q: %w", "unable to start server", err)
+// DefaultShutdownTimeout is the default time given to requests and runs to finish.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// worker blocks until a worker is free, or ctx is done. The returned function frees the worker.
+func (s *Server) worker(ctx context.Context) (func(), error) {
+	s.once.Do(func() {
+		n := s.Workers
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		s.workers = make(chan struct{}, n)
+	})
+
+	select {
+	case s.workers <- struct{}{}:
+		return func() { <-s.workers }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	gworld.Start()
+}
 
-	genv, err := GuardianNews("environment", -2, s.SummaryService, s.GuardianService, s.TaskerFactory)
-	if err != nil {
-		return fmt.Errorf("%q: %w", "unable to start server", err)
+// source returns the news source with the given name.
+func (s *Server) source(name string) (paperboy.NewsSource, error) {
+	for _, ns := range s.NewsSources {
+		if ns.Name() == name {
+			return ns, nil
+		}
 	}
-	genv.Start()
+	return nil, fmt.Errorf("news source %q not found", name)
+}
 
-	gtech, err := GuardianNews("technology", -2, s.SummaryService, s.GuardianService, s.TaskerFactory)
-	if err != nil {
+// Run starts the tasks, and the server at the designated port, until ctx is cancelled.
+// The server then stops accepting requests, and waits up to ShutdownTimeout for requests
+// and ingestion runs in progress to finish, before cancelling them.
+func (s *Server) Run(ctx context.Context, port int) error {
+	conf := paperboy.Config{Jobs: append([]paperboy.Job(nil), s.Jobs...)}
+	if s.Jobs == nil {
+		conf.Jobs = append(conf.Jobs, DefaultJobs...)
+	}
+	conf.SetDefaults()
+	if err := conf.Validate(); err != nil {
 		return fmt.Errorf("%q: %w", "unable to start server", err)
 	}
-	gtech.Start()
+	s.jobs = conf.Jobs
+
+	// Start the tasks. Runs are not given ctx, so that they may finish during shutdown.
+	taskers := make([]paperboy.Tasker, 0, len(conf.Jobs))
+	for _, job := range conf.Jobs {
+		ns, err := s.source(job.Source)
+		if err != nil {
+			s.shutdown(nil, taskers)
+			return fmt.Errorf("%q: %w", "unable to start server", err)
+		}
 
-	gsci, err := GuardianNews("science", -2, s.SummaryService, s.GuardianService, s.TaskerFactory)
-	if err != nil {
+		t, err := s.News(job, ns)
+		if err != nil {
+			s.shutdown(nil, taskers)
+			return fmt.Errorf("%q: %w", "unable to start server", err)
+		}
+		t.Start(context.Background())
+		taskers = append(taskers, t)
+	}
+
+	if s.DeadLetterService != nil {
+		t, err := s.DeadLetters()
+		if err != nil {
+			s.shutdown(nil, taskers)
+			return fmt.Errorf("%q: %w", "unable to start server", err)
+		}
+		t.Start(context.Background())
+		taskers = append(taskers, t)
+	}
+
+	srv := &http.Server{Addr: fmt.Sprintf(":%v", port), Handler: s.Handler}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		s.shutdown(nil, taskers)
 		return fmt.Errorf("%q: %w", "unable to start server", err)
+	case <-ctx.Done():
 	}
-	gsci.Start()
 
-	http.ListenAndServe(fmt.Sprintf(":%v", port), s.Handler)
+	s.shutdown(srv, taskers)
 	return nil
 }
+
+// shutdown gracefully stops the HTTP server and the taskers, within ShutdownTimeout.
+func (s *Server) shutdown(srv *http.Server, taskers []paperboy.Tasker) {
+	timeout := s.ShutdownTimeout
+	if timeout == 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	log.Printf("shutting down, waiting up to %v...\n", timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	if srv != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Printf("unable to drain requests: %v\n", err)
+			}
+		}()
+	}
+	for _, t := range taskers {
+		wg.Add(1)
+		go func(t paperboy.Tasker) {
+			defer wg.Done()
+			if err := t.Stop(ctx); err != nil {
+				log.Printf("unable to finish task: %v\n", err)
+			}
+		}(t)
+	}
+
+	wg.Wait()
+	log.Println("shut down")
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+	"log"
+	"paperboy-back"
+	"paperboy-back/news"
+	"time"
+)
+
+// DefaultStoryWindow is the default time within which summaries are compared.
+const DefaultStoryWindow = 48 * time.Hour
+
+// cluster adds the stored summary to the story of the most similar recent summary, or to a
+// story of its own, if the server has a StoryService. A summary which was already clustered
+// stays in its story. Failures are logged, as the summary itself has been stored.
+func (s *Server) cluster(ctx context.Context, summ *paperboy.Summary) {
+	if s.StoryService == nil {
+		return
+	}
+	window := s.StoryWindow
+	if window == 0 {
+		window = DefaultStoryWindow
+	}
+
+	// Stories are read and written by one summary at a time, so that concurrent jobs
+	// do not start separate stories for the same news.
+	s.storiesMu.Lock()
+	defer s.storiesMu.Unlock()
+
+	m := news.Member(summ)
+	stories, err := s.StoryService.Recent(ctx, m.Date.Add(-window), m.Date.Add(window))
+	if err != nil {
+		log.Printf("[%s] %v\n", m.ContentID, err)
+		return
+	}
+
+	st := member(stories, m.ContentID)
+	if st == nil {
+		st = news.Match(stories, m)
+	}
+	if st == nil {
+		st = &paperboy.Story{ID: m.ContentID}
+	}
+	news.Join(st, m)
+
+	if err := s.StoryService.SaveStory(ctx, st); err != nil {
+		log.Printf("[%s] %v\n", m.ContentID, err)
+	}
+}
+
+// member returns the story which the summary with the given contentID is a member of, if any.
+func member(stories []*paperboy.Story, contentID string) *paperboy.Story {
+	for _, st := range stories {
+		for _, m := range st.Members {
+			if m.ContentID == contentID {
+				return st
+			}
+		}
+	}
+	return nil
+}
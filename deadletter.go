@@ -0,0 +1,43 @@
+package paperboy
+
+import (
+	"context"
+	"time"
+)
+
+// DeadLetter is an article which failed to be ingested, kept with its error so that it may be
+// investigated, and retried.
+type DeadLetter struct {
+	ID       string `bson:"_id"`
+	Job      string
+	Result   Result
+	Stage    string
+	Error    string
+	Attempts int
+	Created  time.Time
+	Updated  time.Time
+}
+
+// DeadLetterService defines the functionality provided by the service.
+//
+//	Add: stores the dead letter, or updates the article and error of an existing one,
+//		leaving its attempts as is.
+//	Attempt: counts a failed retry of the dead letter, keeping its latest error.
+//	DeadLetter: returns the dead letter with the given id.
+//	DeadLetters: returns up to size dead letters, least recently attempted first.
+//	Retryable: returns up to size dead letters attempted fewer than maxAttempts times,
+//		least recently attempted first.
+//	Discard: removes the dead letter with the given id.
+type DeadLetterService interface {
+	Add(ctx context.Context, dl *DeadLetter) error
+	Attempt(ctx context.Context, dl *DeadLetter) error
+	DeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+	DeadLetters(ctx context.Context, size int) ([]*DeadLetter, error)
+	Retryable(ctx context.Context, maxAttempts, size int) ([]*DeadLetter, error)
+	Discard(ctx context.Context, id string) error
+}
+
+// A DeadLetterRetrier ingests a dead letter again, and discards it once stored.
+type DeadLetterRetrier interface {
+	Retry(ctx context.Context, id string) error
+}
@@ -0,0 +1,221 @@
+package paperboy
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Job describes the periodic ingestion of a section from a news source.
+//
+//	Name: the name of the job, defaults to the source and section.
+//	Source: the name of the NewsSource, such as 'guardian'.
+//	Section: the section to fetch, such as 'world'.
+//	Params: source-specific query parameters, such as the url of a feed.
+//	Period: the time between runs.
+//	Schedule: cron expressions of when to run, used instead of the period if set.
+//	Timezone: the IANA time zone of the schedule, such as 'Europe/London', defaults to UTC.
+//	RecoverPeriod: the time before retrying after a run has failed.
+//	Backoff: how the time between retries grows, while runs keep failing.
+//	Lookback: how far back to fetch articles on each run.
+//	PageSize: the maximum number of articles requested per page.
+//	MaxPages: the maximum number of pages requested per run.
+//	Rules: the filter rules deciding which articles are ingested, reloadable at runtime.
+//	Options: the summarization options, defaults are used if unset.
+type Job struct {
+	Name          string            `yaml:"name"`
+	Source        string            `yaml:"source"`
+	Section       string            `yaml:"section"`
+	Params        map[string]string `yaml:"params"`
+	Period        time.Duration     `yaml:"period"`
+	Schedule      []string          `yaml:"schedule"`
+	Timezone      string            `yaml:"timezone"`
+	RecoverPeriod time.Duration     `yaml:"recover_period"`
+	Backoff       JobBackoff        `yaml:"backoff"`
+	Lookback      time.Duration     `yaml:"lookback"`
+	PageSize      int               `yaml:"page_size"`
+	MaxPages      int               `yaml:"max_pages"`
+	Rules         []Rule            `yaml:"rules"`
+	Options       SummarizeOptions  `yaml:"summarize"`
+}
+
+// JobBackoff configures the Backoff of a job. Unset settings use the defaults, while an
+// explicit 0 turns the setting off: retries do not grow, are not capped, are not varied,
+// or never stop, respectively.
+type JobBackoff struct {
+	Multiplier  *float64       `yaml:"multiplier"`
+	Max         *time.Duration `yaml:"max"`
+	Jitter      *float64       `yaml:"jitter"`
+	MaxAttempts *int           `yaml:"max_attempts"`
+}
+
+// Backoff returns the configured backoff, unset settings are turned off.
+func (b JobBackoff) Backoff() Backoff {
+	var bo Backoff
+	if b.Multiplier != nil {
+		bo.Multiplier = *b.Multiplier
+	}
+	if b.Max != nil {
+		bo.Max = *b.Max
+	}
+	if b.Jitter != nil {
+		bo.Jitter = *b.Jitter
+	}
+	if b.MaxAttempts != nil {
+		bo.MaxAttempts = *b.MaxAttempts
+	}
+	return bo
+}
+
+// Config contains the configuration of the application, such as the ingestion jobs.
+//
+//	Workers: the number of articles summarized at once, across all jobs, defaults to GOMAXPROCS.
+//	Jobs: the ingestion jobs.
+type Config struct {
+	Workers int   `yaml:"workers"`
+	Jobs    []Job `yaml:"jobs"`
+}
+
+// A Reloader reloads the parts of the configuration which may change at runtime.
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// Defaults for any job setting left unset.
+const (
+	DefaultPeriod        = 1 * time.Hour
+	DefaultRecoverPeriod = 5 * time.Minute
+	DefaultMultiplier    = 2
+	DefaultMaxBackoff    = 1 * time.Hour
+	DefaultJitter        = 0.1
+	DefaultMaxAttempts   = 5
+	DefaultLookback      = 2 * time.Hour
+	DefaultPageSize      = 50
+	DefaultMaxPages      = 10
+)
+
+// SetDefaults fills in any unset job settings.
+func (c *Config) SetDefaults() {
+	if c.Workers == 0 {
+		c.Workers = runtime.GOMAXPROCS(0)
+	}
+	for i := range c.Jobs {
+		j := &c.Jobs[i]
+		if j.Name == "" {
+			j.Name = strings.TrimSpace(strings.Title(j.Source) + " " + strings.Title(j.Section))
+		}
+		if j.Period == 0 {
+			j.Period = DefaultPeriod
+		}
+		if j.RecoverPeriod == 0 {
+			j.RecoverPeriod = DefaultRecoverPeriod
+		}
+		if j.Backoff.Multiplier == nil {
+			m := float64(DefaultMultiplier)
+			j.Backoff.Multiplier = &m
+		}
+		if j.Backoff.Max == nil {
+			m := DefaultMaxBackoff
+			j.Backoff.Max = &m
+		}
+		if j.Backoff.Jitter == nil {
+			jt := DefaultJitter
+			j.Backoff.Jitter = &jt
+		}
+		if j.Backoff.MaxAttempts == nil {
+			m := DefaultMaxAttempts
+			j.Backoff.MaxAttempts = &m
+		}
+		if j.Lookback == 0 {
+			j.Lookback = DefaultLookback
+		}
+		if j.PageSize == 0 {
+			j.PageSize = DefaultPageSize
+		}
+		if j.MaxPages == 0 {
+			j.MaxPages = DefaultMaxPages
+		}
+	}
+}
+
+// Validate returns an error listing every invalid job setting, if any.
+func (c *Config) Validate() error {
+	var errs []string
+	names := make(map[string]bool)
+
+	if c.Workers < 0 {
+		errs = append(errs, fmt.Sprintf("workers must not be negative, got %d", c.Workers))
+	}
+
+	for i, j := range c.Jobs {
+		invalid := func(format string, a ...interface{}) {
+			errs = append(errs, fmt.Sprintf("jobs[%d] (%s): ", i, j.Name)+fmt.Sprintf(format, a...))
+		}
+
+		if j.Source == "" {
+			invalid("source is required")
+		}
+		if j.Section == "" {
+			invalid("section is required")
+		}
+		if names[j.Name] {
+			invalid("name is not unique")
+		}
+		names[j.Name] = true
+
+		if j.Period <= 0 {
+			invalid("period must be positive, got %v", j.Period)
+		}
+		for si, expr := range j.Schedule {
+			// Parsed as the tasker does, so that a bad expression is reported here.
+			if _, err := cron.ParseStandard(expr); err != nil {
+				invalid("schedule[%d] %q is invalid: %v", si, expr, err)
+			}
+		}
+		if _, err := time.LoadLocation(j.Timezone); err != nil {
+			invalid("timezone %q is unknown", j.Timezone)
+		}
+		if j.RecoverPeriod <= 0 {
+			invalid("recover_period must be positive, got %v", j.RecoverPeriod)
+		}
+		b := j.Backoff.Backoff()
+		if b.Multiplier != 0 && b.Multiplier < 1 {
+			invalid("backoff.multiplier must be 0 or at least 1, got %v", b.Multiplier)
+		}
+		if b.Max != 0 && b.Max < j.RecoverPeriod {
+			invalid("backoff.max must be 0 or at least recover_period, got %v", b.Max)
+		}
+		if b.Jitter < 0 || b.Jitter > 1 {
+			invalid("backoff.jitter must be between 0 and 1, got %v", b.Jitter)
+		}
+		if b.MaxAttempts < 0 {
+			invalid("backoff.max_attempts must not be negative, got %d", b.MaxAttempts)
+		}
+		if j.Lookback <= 0 {
+			invalid("lookback must be positive, got %v", j.Lookback)
+		}
+		if j.PageSize <= 0 || j.PageSize > 200 {
+			invalid("page_size must be between 1 and 200, got %d", j.PageSize)
+		}
+		if j.MaxPages <= 0 {
+			invalid("max_pages must be positive, got %d", j.MaxPages)
+		}
+		for ri := range j.Rules {
+			if err := j.Rules[ri].Validate(); err != nil {
+				invalid("rules[%d]: %v", ri, err)
+			}
+		}
+		if j.Options.Sentences < 0 || j.Options.Keywords < 0 {
+			invalid("summarize options must not be negative")
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n\t%s", strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"paperboy-back"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ArchiveService is a MongoDB implementation of paperboy.ArchiveService.
+type ArchiveService struct {
+	col *mongo.Collection
+}
+
+var _ paperboy.ArchiveService = (*ArchiveService)(nil)
+
+// NewArchiveService returns a pointer to ArchiveService with the MongoDB collection configured.
+func NewArchiveService(db *DB) *ArchiveService {
+	return &ArchiveService{col: db.db.Collection("archive")}
+}
+
+type archived struct {
+	ContentID string          `bson:"_id"`
+	Job       string          `bson:"job"`
+	Date      time.Time       `bson:"date"`
+	Archived  time.Time       `bson:"archived"`
+	Result    paperboy.Result `bson:"result"`
+}
+
+// Archive stores the articles fetched by the job, replacing earlier versions.
+func (s *ArchiveService) Archive(ctx context.Context, job string, res []*paperboy.Result) error {
+	if len(res) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	models := make([]mongo.WriteModel, len(res))
+	for idx, r := range res {
+		// Results are normalized to RFC 3339 dates by the news sources.
+		date, _ := time.Parse(time.RFC3339, r.Date)
+		doc := archived{ContentID: r.ContentID, Job: job, Date: date, Archived: now, Result: *r}
+		models[idx] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": r.ContentID}).
+			SetReplacement(doc).
+			SetUpsert(true)
+	}
+
+	opts := options.BulkWrite().SetOrdered(false)
+	if _, err := s.col.BulkWrite(ctx, models, opts); err != nil {
+		return fmt.Errorf("%q: %w", "unable to archive articles", err)
+	}
+	return nil
+}
+
+// Walk calls fn with each of the job's articles published between from and to, oldest first,
+// stopping at the first error.
+func (s *ArchiveService) Walk(ctx context.Context, job string, from, to time.Time, fn func(*paperboy.Result) error) error {
+	filter := bson.M{
+		"job":  job,
+		"date": bson.M{"$gte": from.UTC(), "$lt": to.UTC()},
+	}
+	opts := options.Find().SetSort(bson.M{"date": 1})
+
+	cursor, err := s.col.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to find archived articles", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var a archived
+		if err := cursor.Decode(&a); err != nil {
+			return fmt.Errorf("%q: %w", "unable to decode archived article", err)
+		}
+		if err := fn(&a.Result); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
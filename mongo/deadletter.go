@@ -0,0 +1,119 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"paperboy-back"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeadLetterService is a MongoDB implementation of paperboy.DeadLetterService.
+type DeadLetterService struct {
+	col *mongo.Collection
+}
+
+var _ paperboy.DeadLetterService = (*DeadLetterService)(nil)
+
+// NewDeadLetterService returns a pointer to DeadLetterService with the MongoDB collection configured.
+func NewDeadLetterService(db *DB) *DeadLetterService {
+	return &DeadLetterService{col: db.db.Collection("deadletters")}
+}
+
+// Add stores the dead letter, or updates the article and latest error of an existing one.
+// Attempts are only counted by Attempt, as the article may fail again on every ingestion.
+func (s *DeadLetterService) Add(ctx context.Context, dl *paperboy.DeadLetter) error {
+	now := time.Now().UTC()
+	opts := options.Update().SetUpsert(true)
+	filter := bson.M{"_id": dl.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"job":     dl.Job,
+			"result":  dl.Result,
+			"stage":   dl.Stage,
+			"error":   dl.Error,
+			"updated": now,
+		},
+		"$setOnInsert": bson.M{"created": now, "attempts": 1},
+	}
+
+	if _, err := s.col.UpdateOne(ctx, filter, update, opts); err != nil {
+		return fmt.Errorf("%q: %w", "unable to add dead letter", err)
+	}
+	return nil
+}
+
+// Attempt increments the attempts of the dead letter, keeping the latest error.
+func (s *DeadLetterService) Attempt(ctx context.Context, dl *paperboy.DeadLetter) error {
+	filter := bson.M{"_id": dl.ID}
+	update := bson.M{
+		"$set": bson.M{"error": dl.Error, "updated": time.Now().UTC()},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	res, err := s.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to count dead letter attempt", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("dead letter %q not found", dl.ID)
+	}
+	return nil
+}
+
+// DeadLetter returns the dead letter with the given id.
+func (s *DeadLetterService) DeadLetter(ctx context.Context, id string) (*paperboy.DeadLetter, error) {
+	var dl paperboy.DeadLetter
+	if err := s.col.FindOne(ctx, bson.M{"_id": id}).Decode(&dl); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find dead letter", err)
+	}
+	return &dl, nil
+}
+
+// DeadLetters returns up to size dead letters, least recently attempted first.
+func (s *DeadLetterService) DeadLetters(ctx context.Context, size int) ([]*paperboy.DeadLetter, error) {
+	return s.find(ctx, bson.M{}, size)
+}
+
+// Retryable returns up to size dead letters attempted fewer than maxAttempts times, least
+// recently attempted first, so that exhausted dead letters never crowd out the others.
+func (s *DeadLetterService) Retryable(ctx context.Context, maxAttempts, size int) ([]*paperboy.DeadLetter, error) {
+	return s.find(ctx, bson.M{"attempts": bson.M{"$lt": maxAttempts}}, size)
+}
+
+// find returns up to size dead letters matching the filter, least recently attempted first.
+func (s *DeadLetterService) find(ctx context.Context, filter bson.M, size int) ([]*paperboy.DeadLetter, error) {
+	opts := options.Find().SetSort(bson.M{"updated": 1}).SetLimit(int64(size))
+
+	cursor, err := s.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find dead letters", err)
+	}
+	defer cursor.Close(ctx)
+
+	dls := make([]*paperboy.DeadLetter, 0, size)
+	for cursor.Next(ctx) {
+		var dl paperboy.DeadLetter
+		if err := cursor.Decode(&dl); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode dead letter", err)
+		}
+		dls = append(dls, &dl)
+	}
+
+	return dls, nil
+}
+
+// Discard removes the dead letter with the given id.
+func (s *DeadLetterService) Discard(ctx context.Context, id string) error {
+	res, err := s.col.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to discard dead letter", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("dead letter %q not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"paperboy-back"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MarkService is a MongoDB implementation of paperboy.MarkService.
+type MarkService struct {
+	col *mongo.Collection
+}
+
+var _ paperboy.MarkService = (*MarkService)(nil)
+
+// NewMarkService returns a pointer to MarkService with the MongoDB collection configured.
+func NewMarkService(db *DB) *MarkService {
+	return &MarkService{col: db.db.Collection("marks")}
+}
+
+type mark struct {
+	Job  string    `bson:"_id"`
+	Date time.Time `bson:"date"`
+}
+
+// Mark returns the high-water mark of the job, or the zero time if it has none.
+func (s *MarkService) Mark(ctx context.Context, job string) (time.Time, error) {
+	var m mark
+	err := s.col.FindOne(ctx, bson.M{"_id": job}).Decode(&m)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, fmt.Errorf("%q: %w", "unable to find mark", err)
+	}
+	return m.Date.UTC(), nil
+}
+
+// SetMark stores the high-water mark of the job, it never moves a mark backwards.
+func (s *MarkService) SetMark(ctx context.Context, job string, date time.Time) error {
+	opts := options.Update().SetUpsert(true)
+	filter := bson.M{"_id": job}
+	update := bson.M{"$max": bson.M{"date": date.UTC()}}
+
+	_, err := s.col.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return fmt.Errorf("%q: %w", "unable to update mark", err)
+	}
+	return nil
+}
@@ -13,36 +13,60 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// SummaryService is a MongoDB implementation of paperboy.SummaryService.
-type SummaryService struct {
-	col *mongo.Collection
+// DB is a connection to the paperboy MongoDB database, shared by the services.
+type DB struct {
+	client *mongo.Client
+	db     *mongo.Database
 }
 
-var _ paperboy.SummaryService = (*SummaryService)(nil)
-
-// Open returns a pointer to SummaryService with the MongoDB collection configured.
-func Open(uri, key string) (*SummaryService, error) {
+// Connect returns a pointer to DB connected to the paperboy database.
+func Connect(ctx context.Context, uri, key string) (*DB, error) {
 	client, err := mongo.Connect(
-		context.TODO(),
+		ctx,
 		options.Client().ApplyURI(strings.ReplaceAll(uri, "<password>", key)),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to connect to collection", err)
+		return nil, fmt.Errorf("%q: %w", "unable to connect to database", err)
 	}
-	collection := client.Database("paperboy").Collection("develop")
 
-	return &SummaryService{col: collection}, nil
+	return &DB{client: client, db: client.Database("paperboy")}, nil
+}
+
+// Close disconnects from the database, waiting for operations in progress until ctx is done.
+func (db *DB) Close(ctx context.Context) error {
+	if err := db.client.Disconnect(ctx); err != nil {
+		return fmt.Errorf("%q: %w", "unable to disconnect from database", err)
+	}
+	return nil
+}
+
+// SummaryService is a MongoDB implementation of paperboy.SummaryService, and
+// paperboy.RevisionService.
+type SummaryService struct {
+	col  *mongo.Collection
+	revs *mongo.Collection
+}
+
+var _ paperboy.SummaryService = (*SummaryService)(nil)
+var _ paperboy.RevisionService = (*SummaryService)(nil)
+
+// NewSummaryService returns a pointer to SummaryService with the MongoDB collections configured.
+func NewSummaryService(db *DB) *SummaryService {
+	return &SummaryService{
+		col:  db.db.Collection("develop"),
+		revs: db.db.Collection("revisions"),
+	}
 }
 
 // Summary returns a pointer to a summary object for a given objectID.
-func (s *SummaryService) Summary(id string) (*paperboy.Summary, error) {
+func (s *SummaryService) Summary(ctx context.Context, id string) (*paperboy.Summary, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, fmt.Errorf("%q: %w", "invalid objectId", err)
 	}
 
 	var res paperboy.Summary
-	err = s.col.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&res)
+	err = s.col.FindOne(ctx, bson.M{"_id": objectID}).Decode(&res)
 	if err != nil {
 		return nil, fmt.Errorf("%q: %w", "objectId not found", err)
 	}
@@ -56,7 +80,7 @@ type hex struct {
 // Summaries returns a slice of the most recent summaries with a given sectionID such as 'world' or 'tech'.
 // A starting objectID and page number must also be provided for pagination.
 //		sectionID: nil -> articles in all sections
-func (s *SummaryService) Summaries(sectionID string, endDate time.Time, size int) ([]*paperboy.Summary, string, error) {
+func (s *SummaryService) Summaries(ctx context.Context, sectionID string, endDate time.Time, size int) ([]*paperboy.Summary, string, error) {
 	// Query range filter using the default indexed (objectid) _id field and sectionid.
 	var err error
 
@@ -73,13 +97,13 @@ func (s *SummaryService) Summaries(sectionID string, endDate time.Time, size int
 	opts = append(opts, options.Find().SetLimit(int64(size)))
 
 	// Fetch cursor.
-	cursor, err := s.col.Find(context.TODO(), filters, opts...)
+	cursor, err := s.col.Find(ctx, filters, opts...)
 	if err != nil {
 		return nil, "", fmt.Errorf("%q: %w", "cursor not found", err)
 	}
 
 	var res []*paperboy.Summary
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var summ paperboy.Summary
 		err = cursor.Decode(&summ)
 		if err != nil {
@@ -102,7 +126,7 @@ func (s *SummaryService) Summaries(sectionID string, endDate time.Time, size int
 
 // Search returns a list of summaries found using Mongo's fuzzy search
 // with the text index being the 'keywords' generated previously.
-func (s *SummaryService) Search(query string, size int) ([]*paperboy.Summary, error) {
+func (s *SummaryService) Search(ctx context.Context, query string, size int) ([]*paperboy.Summary, error) {
 	var err error
 
 	// Configure search options, and filter.
@@ -113,13 +137,13 @@ func (s *SummaryService) Search(query string, size int) ([]*paperboy.Summary, er
 		options.Find().SetLimit(int64(size)),
 	}
 
-	cursor, err := s.col.Find(context.TODO(), filters, opts...)
+	cursor, err := s.col.Find(ctx, filters, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("%q: %w", "unable to perform search", err)
 	}
 
 	var summaries []*paperboy.Summary
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		var summ paperboy.Summary
 		err = cursor.Decode(&summ)
 		if err != nil {
@@ -136,14 +160,123 @@ func (s *SummaryService) Search(query string, size int) ([]*paperboy.Summary, er
 }
 
 // Create inserts a summary into the database if possible, otherwise,
-// it will update the existing entry.
-func (s *SummaryService) Create(summary *paperboy.Summary) error {
+// it will update the existing entry. If the summarization of an existing
+// entry has changed, the previous entry is kept as a revision.
+func (s *SummaryService) Create(ctx context.Context, summary *paperboy.Summary) error {
 	// Configure options, filter, and update.
-	opts := options.Update().SetUpsert(true)
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
 	filter := bson.M{"info.contentid": summary.Info.ContentID}
 	update := bson.M{"$set": summary}
 
-	_, err := s.col.UpdateOne(context.TODO(), filter, update, opts)
+	var prev paperboy.Summary
+	err := s.col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&prev)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("%q: %w", "unable to update document", err)
+	}
+
+	if !revised(&prev, summary) {
+		return nil
+	}
+
+	rev := paperboy.Revision{
+		ContentID: summary.Info.ContentID,
+		Revised:   time.Now().UTC(),
+		Summary:   prev,
+	}
+	if _, err := s.revs.InsertOne(ctx, rev); err != nil {
+		return fmt.Errorf("%q: %w", "unable to insert revision", err)
+	}
+	return nil
+}
+
+// revised returns whether the summarization differs between the two summaries.
+func revised(prev, next *paperboy.Summary) bool {
+	if len(prev.Article.SummaryText) != len(next.Article.SummaryText) ||
+		len(prev.Article.Keywords) != len(next.Article.Keywords) {
+		return true
+	}
+	for idx, sen := range prev.Article.SummaryText {
+		if sen.Sentence != next.Article.SummaryText[idx].Sentence {
+			return true
+		}
+	}
+	for idx, kw := range prev.Article.Keywords {
+		if kw.Word != next.Article.Keywords[idx].Word {
+			return true
+		}
+	}
+	return false
+}
+
+// Revisions returns the prior versions of the summary with the given objectID, newest first.
+func (s *SummaryService) Revisions(ctx context.Context, id string) ([]*paperboy.Revision, error) {
+	summ, err := s.Summary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"contentid": summ.Info.ContentID}
+	opts := options.Find().SetSort(bson.M{"revised": -1})
+
+	cursor, err := s.revs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find revisions", err)
+	}
+	defer cursor.Close(ctx)
+
+	revs := make([]*paperboy.Revision, 0)
+	for cursor.Next(ctx) {
+		var rev paperboy.Revision
+		if err := cursor.Decode(&rev); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode revision", err)
+		}
+		rev.Summary.ObjectID = id
+		revs = append(revs, &rev)
+	}
+
+	return revs, nil
+}
+
+// Hashes returns the hashes of the summaries with the given contentIDs, keyed by contentID.
+// Summaries which do not exist, or have no hash, are omitted.
+func (s *SummaryService) Hashes(ctx context.Context, contentIDs []string) (map[string]string, error) {
+	filter := bson.M{"info.contentid": bson.M{"$in": contentIDs}}
+	opts := options.Find().SetProjection(bson.M{"info.contentid": 1, "hash": 1})
+
+	cursor, err := s.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find hashes", err)
+	}
+	defer cursor.Close(ctx)
+
+	hashes := make(map[string]string)
+	for cursor.Next(ctx) {
+		var summ paperboy.Summary
+		if err := cursor.Decode(&summ); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode summary", err)
+		}
+		if summ.Hash != "" {
+			hashes[summ.Info.ContentID] = summ.Hash
+		}
+	}
+
+	return hashes, nil
+}
+
+// UpdateInfo updates the metadata, title, trail text, and image of an existing summary,
+// leaving its summarization as is.
+func (s *SummaryService) UpdateInfo(ctx context.Context, summary *paperboy.Summary) error {
+	filter := bson.M{"info.contentid": summary.Info.ContentID}
+	update := bson.M{"$set": bson.M{
+		"info":              summary.Info,
+		"article.title":     summary.Article.Title,
+		"article.trailtext": summary.Article.TrailText,
+		"image":             summary.Image,
+	}}
+
+	_, err := s.col.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return fmt.Errorf("%q: %w", "unable to update document", err)
 	}
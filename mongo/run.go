@@ -0,0 +1,55 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"paperboy-back"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RunService is a MongoDB implementation of paperboy.RunService.
+type RunService struct {
+	col *mongo.Collection
+}
+
+var _ paperboy.RunService = (*RunService)(nil)
+
+// NewRunService returns a pointer to RunService with the MongoDB collection configured.
+func NewRunService(db *DB) *RunService {
+	return &RunService{col: db.db.Collection("runs")}
+}
+
+// Record stores the run.
+func (s *RunService) Record(ctx context.Context, r *paperboy.Run) error {
+	if _, err := s.col.InsertOne(ctx, r); err != nil {
+		return fmt.Errorf("%q: %w", "unable to insert run", err)
+	}
+	return nil
+}
+
+// Runs returns the last n runs of the task, newest first.
+func (s *RunService) Runs(ctx context.Context, task string, n int) ([]*paperboy.Run, error) {
+	filter := bson.M{"task": task}
+	opts := options.Find().SetSort(bson.M{"start": -1}).SetLimit(int64(n))
+
+	cursor, err := s.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find runs", err)
+	}
+	defer cursor.Close(ctx)
+
+	runs := make([]*paperboy.Run, 0, n)
+	for cursor.Next(ctx) {
+		var r paperboy.Run
+		if err := cursor.Decode(&r); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode run", err)
+		}
+		r.Start, r.End = r.Start.UTC(), r.End.UTC()
+		runs = append(runs, &r)
+	}
+
+	return runs, nil
+}
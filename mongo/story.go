@@ -0,0 +1,109 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"paperboy-back"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StoryService is a MongoDB implementation of paperboy.StoryService.
+type StoryService struct {
+	col  *mongo.Collection
+	sums *mongo.Collection
+}
+
+var _ paperboy.StoryService = (*StoryService)(nil)
+
+// NewStoryService returns a pointer to StoryService with the MongoDB collections configured.
+func NewStoryService(db *DB) *StoryService {
+	return &StoryService{
+		col:  db.db.Collection("stories"),
+		sums: db.db.Collection("develop"),
+	}
+}
+
+// Stories returns the stories with at least min summaries in the section, latest first,
+// along with their representative summaries.
+func (s *StoryService) Stories(ctx context.Context, sectionID string, min, size int) ([]*paperboy.Story, error) {
+	filter := bson.M{"size": bson.M{"$gte": min}}
+	if len(sectionID) > 0 && sectionID != "all" {
+		filter["members.sectionid"] = sectionID
+	}
+	opts := options.Find().SetSort(bson.M{"date": -1}).SetLimit(int64(size))
+
+	stories, err := s.find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reps := make([]string, 0, len(stories))
+	for _, st := range stories {
+		reps = append(reps, st.Representative)
+	}
+	cursor, err := s.sums.Find(ctx, bson.M{"info.contentid": bson.M{"$in": reps}})
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find summaries", err)
+	}
+	defer cursor.Close(ctx)
+
+	summaries := make(map[string]*paperboy.Summary, len(reps))
+	for cursor.Next(ctx) {
+		var summ paperboy.Summary
+		if err := cursor.Decode(&summ); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode summary", err)
+		}
+		var h hex
+		cursor.Decode(&h)
+		summ.ObjectID = h.ID.Hex()
+		summaries[summ.Info.ContentID] = &summ
+	}
+	for _, st := range stories {
+		st.Summary = summaries[st.Representative]
+	}
+
+	return stories, nil
+}
+
+// Recent returns the stories with a summary published between from and to.
+func (s *StoryService) Recent(ctx context.Context, from, to time.Time) ([]*paperboy.Story, error) {
+	filter := bson.M{"members.date": bson.M{"$gte": from.UTC(), "$lte": to.UTC()}}
+	return s.find(ctx, filter)
+}
+
+// find returns the stories matching the filter.
+func (s *StoryService) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*paperboy.Story, error) {
+	cursor, err := s.col.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to find stories", err)
+	}
+	defer cursor.Close(ctx)
+
+	stories := make([]*paperboy.Story, 0)
+	for cursor.Next(ctx) {
+		var st paperboy.Story
+		if err := cursor.Decode(&st); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode story", err)
+		}
+		st.Date = st.Date.UTC()
+		for i := range st.Members {
+			st.Members[i].Date = st.Members[i].Date.UTC()
+		}
+		stories = append(stories, &st)
+	}
+
+	return stories, nil
+}
+
+// SaveStory stores the story, replacing any story with the same ID.
+func (s *StoryService) SaveStory(ctx context.Context, st *paperboy.Story) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.col.ReplaceOne(ctx, bson.M{"_id": st.ID}, st, opts); err != nil {
+		return fmt.Errorf("%q: %w", "unable to save story", err)
+	}
+	return nil
+}
@@ -1,5 +1,10 @@
 package paperboy
 
+import (
+	"context"
+	"time"
+)
+
 // TypeData contains information about an asset.
 type TypeData struct {
 	Width  int
@@ -48,7 +53,8 @@ type Tag struct {
 	Title string `json:"webTitle"`
 }
 
-// Result contains information on individual articles.
+// Result contains information on individual articles. Every NewsSource
+// normalizes its articles into results, following the Guardian's layout.
 type Result struct {
 	ContentID   string `json:"id"`
 	SectionID   string
@@ -61,10 +67,15 @@ type Result struct {
 	Blocks      Blocks
 }
 
-// Response contains the HTTP response status, and a collection of results.
+// Response contains the HTTP response status, paging information, and a collection of results.
 type Response struct {
-	Status  string
-	Results []*Result
+	Status      string
+	Total       int
+	StartIndex  int
+	PageSize    int
+	CurrentPage int
+	Pages       int
+	Results     []*Result
 }
 
 // Guardian is the HTTP response from the Guardian API.
@@ -72,9 +83,34 @@ type Guardian struct {
 	Response Response
 }
 
-// GuardianService defines the functionality provided by the service.
-type GuardianService interface {
-	Fetch(qparams map[string]string) (*Guardian, error)
-	Extract(res []*Result) ([]*Summary, error)
-	ExtractOne(r *Result) (*Summary, error)
+// Query contains the parameters used to fetch articles from a news source, published
+// between From and To, if set. Sources that page their results fetch at most MaxPages
+// pages of PageSize articles. Params holds source-specific parameters, and is passed
+// through as-is.
+type Query struct {
+	Section  string
+	From     time.Time
+	To       time.Time
+	PageSize int
+	MaxPages int
+	Params   map[string]string
+}
+
+// NewsSource defines the functionality provided by a news provider.
+//
+//	Name: returns the name of the source, such as 'guardian'.
+//	Fetch: returns the articles matching the query, normalized into results.
+type NewsSource interface {
+	Name() string
+	Fetch(ctx context.Context, q Query) ([]*Result, error)
+}
+
+// MarkService defines the functionality of a store of high-water marks, the
+// publication date of the latest article ingested by each job.
+//
+//	Mark: returns the high-water mark of a job, or the zero time if it has none.
+//	SetMark: stores the high-water mark of a job.
+type MarkService interface {
+	Mark(ctx context.Context, job string) (time.Time, error)
+	SetMark(ctx context.Context, job string, date time.Time) error
 }
@@ -0,0 +1,154 @@
+package news
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"paperboy-back"
+	"strings"
+	"unicode"
+)
+
+// Thresholds of the clustering of summaries into stories.
+const (
+	// MaxDistance is the largest Hamming distance between the SimHashes of near-duplicate texts.
+	MaxDistance = 3
+	// MinOverlap is the smallest overlap of keywords between summaries of the same story.
+	MinOverlap = 0.3
+)
+
+// shingle is the number of words hashed together by SimHash.
+const shingle = 3
+
+// SimHash returns the 64-bit fingerprint of the text, computed over its shingles of words.
+// The fingerprints of near-duplicate texts differ in few bits.
+func SimHash(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	n := len(words) - shingle + 1
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		end := i + shingle
+		if end > len(words) {
+			end = len(words)
+		}
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:end], " ")))
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+
+	var fp uint64
+	for b, w := range weights {
+		if w > 0 {
+			fp |= 1 << b
+		}
+	}
+	return fp
+}
+
+// Member returns the story member of the summary.
+func Member(summ *paperboy.Summary) paperboy.StoryMember {
+	kws := make([]string, 0, len(summ.Article.Keywords))
+	for _, kw := range summ.Article.Keywords {
+		kws = append(kws, strings.ToLower(kw.Word))
+	}
+	return paperboy.StoryMember{
+		ContentID: summ.Info.ContentID,
+		SectionID: summ.Info.SectionID,
+		Title:     summ.Article.Title,
+		Date:      summ.Info.Date,
+		SimHash:   int64(summ.SimHash),
+		Keywords:  kws,
+	}
+}
+
+// Similarity returns how alike two members are, from 0 to 1. Near-duplicate texts are
+// always alike, otherwise it is the overlap (Jaccard index) of their keywords.
+func Similarity(a, b paperboy.StoryMember) float64 {
+	if a.SimHash != 0 && bits.OnesCount64(uint64(a.SimHash^b.SimHash)) <= MaxDistance {
+		return 1
+	}
+	if len(a.Keywords) == 0 || len(b.Keywords) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(a.Keywords))
+	for _, kw := range a.Keywords {
+		set[kw] = true
+	}
+	var shared int
+	union := len(set)
+	for _, kw := range b.Keywords {
+		if set[kw] {
+			shared++
+			set[kw] = false
+		} else if _, ok := set[kw]; !ok {
+			union++
+			set[kw] = false
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+// Match returns the story with the member most similar to m, if any are similar enough.
+func Match(stories []*paperboy.Story, m paperboy.StoryMember) *paperboy.Story {
+	var best *paperboy.Story
+	var max float64
+	for _, st := range stories {
+		for _, o := range st.Members {
+			if sim := Similarity(m, o); sim >= MinOverlap && sim > max {
+				best, max = st, sim
+			}
+		}
+	}
+	return best
+}
+
+// Join adds the member to the story, replacing its earlier version if it is already a member,
+// and updates the story's size, date, and representative.
+func Join(st *paperboy.Story, m paperboy.StoryMember) {
+	found := false
+	for i, o := range st.Members {
+		if o.ContentID == m.ContentID {
+			st.Members[i], found = m, true
+		}
+	}
+	if !found {
+		st.Members = append(st.Members, m)
+	}
+
+	st.Size = len(st.Members)
+	if m.Date.After(st.Date) {
+		st.Date = m.Date
+	}
+
+	// The representative is the member most similar to the others, the earliest on ties.
+	var rep paperboy.StoryMember
+	max := -1.0
+	for _, a := range st.Members {
+		var total float64
+		for _, b := range st.Members {
+			if a.ContentID != b.ContentID {
+				total += Similarity(a, b)
+			}
+		}
+		if total > max || (total == max && a.Date.Before(rep.Date)) {
+			max, rep = total, a
+		}
+	}
+	st.Representative = rep.ContentID
+}
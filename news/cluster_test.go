@@ -0,0 +1,140 @@
+package news
+
+import (
+	"math"
+	"math/bits"
+	"paperboy-back"
+	"testing"
+	"time"
+)
+
+const text = "The city council has approved a plan to build new cycle lanes connecting the city centre " +
+	"to the university, after a vote on Tuesday evening. Construction is expected to begin in the spring."
+
+func TestSimHash(t *testing.T) {
+	// Only the words are hashed, not their case or punctuation.
+	same := "the City Council has approved a plan, to build new cycle-lanes connecting the city centre " +
+		"to the university after a vote on Tuesday evening; construction is expected to begin in the Spring!"
+	far := "A bright comet will be visible to the naked eye this weekend, astronomers say, " +
+		"weather permitting, for the first time in decades."
+
+	if SimHash(text) != SimHash(same) {
+		t.Errorf("same words: got distance %d, want 0", bits.OnesCount64(SimHash(text)^SimHash(same)))
+	}
+	if d := bits.OnesCount64(SimHash(text) ^ SimHash(far)); d <= MaxDistance {
+		t.Errorf("unrelated: got distance %d, want more than %d", d, MaxDistance)
+	}
+	if SimHash("  ...  ") != 0 {
+		t.Error("empty text: expected no fingerprint")
+	}
+	if SimHash("Two words") == 0 {
+		t.Error("short text: expected a fingerprint")
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	// flip returns the fingerprint with its n lowest bits flipped.
+	fp := int64(SimHash(text))
+	flip := func(n int) int64 {
+		return fp ^ int64(1<<uint(n)-1)
+	}
+
+	tests := []struct {
+		name string
+		a, b paperboy.StoryMember
+		want float64
+	}{
+		{"identical text", paperboy.StoryMember{SimHash: fp}, paperboy.StoryMember{SimHash: fp}, 1},
+		{"within distance", paperboy.StoryMember{SimHash: fp}, paperboy.StoryMember{SimHash: flip(MaxDistance)}, 1},
+		{"beyond distance", paperboy.StoryMember{SimHash: fp}, paperboy.StoryMember{SimHash: flip(MaxDistance + 1)}, 0},
+		{"no fingerprints", paperboy.StoryMember{}, paperboy.StoryMember{}, 0},
+		{"no keywords", paperboy.StoryMember{Keywords: []string{"council"}}, paperboy.StoryMember{}, 0},
+		{"keywords",
+			paperboy.StoryMember{SimHash: fp, Keywords: []string{"council", "cycle", "lanes"}},
+			paperboy.StoryMember{SimHash: flip(MaxDistance + 1), Keywords: []string{"cycle", "lanes", "university"}},
+			0.5},
+		{"disjoint keywords",
+			paperboy.StoryMember{Keywords: []string{"council"}},
+			paperboy.StoryMember{Keywords: []string{"comet"}},
+			0},
+		// Duplicates count once, on either side.
+		{"duplicate keywords",
+			paperboy.StoryMember{Keywords: []string{"council", "council", "cycle"}},
+			paperboy.StoryMember{Keywords: []string{"cycle", "cycle", "lanes", "lanes"}},
+			1.0 / 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if got := Similarity(tt.b, tt.a); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("reversed: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// member returns a story member with the given keywords, published on the given day.
+func member(id string, day int, keywords ...string) paperboy.StoryMember {
+	return paperboy.StoryMember{
+		ContentID: id,
+		Date:      time.Date(2021, 3, day, 0, 0, 0, 0, time.UTC),
+		Keywords:  keywords,
+	}
+}
+
+func TestMatch(t *testing.T) {
+	lanes := &paperboy.Story{Members: []paperboy.StoryMember{member("a", 1, "council", "cycle", "lanes")}}
+	comet := &paperboy.Story{Members: []paperboy.StoryMember{member("b", 1, "comet", "weekend")}}
+	stories := []*paperboy.Story{lanes, comet}
+
+	if st := Match(stories, member("c", 2, "cycle", "lanes", "university")); st != lanes {
+		t.Errorf("similar: got %+v, want the lanes story", st)
+	}
+	// The overlap of 1/4 is below MinOverlap.
+	if st := Match(stories, member("d", 2, "comet", "budget", "vote")); st != nil {
+		t.Errorf("dissimilar: got %+v, want none", st)
+	}
+	// The most similar story wins, whatever the order.
+	near := &paperboy.Story{Members: []paperboy.StoryMember{member("e", 1, "cycle", "lanes")}}
+	if st := Match(append(stories, near), member("f", 2, "cycle", "lanes")); st != near {
+		t.Errorf("best: got %+v, want the closest story", st)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	st := &paperboy.Story{}
+	Join(st, member("a", 2, "council", "cycle"))
+	if st.Size != 1 || st.Representative != "a" || !st.Date.Equal(member("", 2).Date) {
+		t.Fatalf("first member: got %+v", st)
+	}
+
+	// b shares a keyword with both a and c, so is the most similar to the others.
+	Join(st, member("c", 3, "lanes", "university"))
+	Join(st, member("b", 1, "cycle", "lanes"))
+	if st.Size != 3 || st.Representative != "b" {
+		t.Fatalf("got size %d, representative %q, want 3, b", st.Size, st.Representative)
+	}
+	if !st.Date.Equal(member("", 3).Date) {
+		t.Errorf("date: got %v, want the latest member's", st.Date)
+	}
+
+	// Rejoining replaces the member, here making a and c alike, so the earliest of them wins.
+	Join(st, member("b", 1, "comet"))
+	Join(st, member("c", 3, "council", "cycle"))
+	if st.Size != 3 || st.Representative != "a" {
+		t.Fatalf("rejoin: got size %d, representative %q, want 3, a", st.Size, st.Representative)
+	}
+}
+
+func TestJoinTie(t *testing.T) {
+	// Members equally alike are tied, the earliest represents the story.
+	st := &paperboy.Story{}
+	Join(st, member("a", 3, "council"))
+	Join(st, member("b", 1, "council"))
+	Join(st, member("c", 2, "council"))
+	if st.Representative != "b" {
+		t.Fatalf("got representative %q, want the earliest, b", st.Representative)
+	}
+}
@@ -0,0 +1,109 @@
+package news
+
+import (
+	"paperboy-back"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Filter decides which articles are ingested, following a job's rules.
+type Filter struct {
+	rules     []rule
+	allowlist bool
+}
+
+// rule is a compiled paperboy.Rule, unset patterns are nil.
+type rule struct {
+	include  bool
+	title    *regexp.Regexp
+	section  *regexp.Regexp
+	tag      *regexp.Regexp
+	author   *regexp.Regexp
+	body     *regexp.Regexp
+	minWords int
+	maxWords int
+}
+
+// NewFilter compiles the rules into a Filter.
+func NewFilter(rules []paperboy.Rule) (*Filter, error) {
+	f := &Filter{rules: make([]rule, len(rules))}
+	for idx, r := range rules {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+
+		f.rules[idx] = rule{
+			include:  r.Action == paperboy.RuleInclude,
+			title:    compile(r.Title),
+			section:  compile(r.Section),
+			tag:      compile(r.Tag),
+			author:   compile(r.Author),
+			body:     compile(r.Body),
+			minWords: r.MinWords,
+			maxWords: r.MaxWords,
+		}
+		f.allowlist = f.allowlist || f.rules[idx].include
+	}
+	return f, nil
+}
+
+// Keep returns whether the article should be ingested.
+func (f *Filter) Keep(r *paperboy.Result) bool {
+	for _, rl := range f.rules {
+		if rl.match(r) {
+			return rl.include
+		}
+	}
+	return !f.allowlist
+}
+
+func (rl *rule) match(r *paperboy.Result) bool {
+	if rl.title != nil && !rl.title.MatchString(r.Title) {
+		return false
+	}
+	if rl.section != nil && !rl.section.MatchString(r.SectionID) {
+		return false
+	}
+	if rl.body != nil && !rl.body.MatchString(r.Fields.BodyText) {
+		return false
+	}
+	if rl.tag != nil && !anyTag(r, func(t paperboy.Tag) bool {
+		return rl.tag.MatchString(t.ID) || rl.tag.MatchString(t.Title)
+	}) {
+		return false
+	}
+	if rl.author != nil && !anyTag(r, func(t paperboy.Tag) bool {
+		return t.Type == "contributor" && rl.author.MatchString(t.Title)
+	}) {
+		return false
+	}
+
+	if rl.minWords > 0 || rl.maxWords > 0 {
+		words, err := strconv.Atoi(r.Fields.WordCount)
+		if err != nil {
+			words = len(strings.Fields(r.Fields.BodyText))
+		}
+		if words < rl.minWords || rl.maxWords > 0 && words > rl.maxWords {
+			return false
+		}
+	}
+	return true
+}
+
+func anyTag(r *paperboy.Result, fn func(paperboy.Tag) bool) bool {
+	for _, t := range r.Tags {
+		if fn(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// compile returns the compiled pattern, or nil if it is empty. Patterns are validated beforehand.
+func compile(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	return regexp.MustCompile(pattern)
+}
@@ -0,0 +1,96 @@
+package news
+
+import (
+	"paperboy-back"
+	"strings"
+	"testing"
+)
+
+// article returns a result with the given title, tags, and body of n words.
+func article(title string, words int, tags ...paperboy.Tag) *paperboy.Result {
+	return &paperboy.Result{
+		Title:     title,
+		SectionID: "world",
+		Fields:    paperboy.Fields{BodyText: strings.TrimSpace(strings.Repeat("word ", words))},
+		Tags:      tags,
+	}
+}
+
+func TestFilter(t *testing.T) {
+	obituary := paperboy.Tag{ID: "tone/obituaries", Type: "tone", Title: "Obituaries"}
+	doe := paperboy.Tag{ID: "profile/jane-doe", Type: "contributor", Title: "Jane Doe"}
+	roe := paperboy.Tag{ID: "profile/john-roe", Type: "keyword", Title: "John Roe"}
+
+	tests := []struct {
+		name  string
+		rules []paperboy.Rule
+		r     *paperboy.Result
+		want  bool
+	}{
+		{"no rules", nil, article("Storm", 100), true},
+
+		// Without include rules, articles matching no rule are kept.
+		{"exclude matched", []paperboy.Rule{{Action: "exclude", Title: "(?i)storm"}}, article("Storm", 100), false},
+		{"exclude unmatched", []paperboy.Rule{{Action: "exclude", Title: "(?i)storm"}}, article("Budget", 100), true},
+
+		// With an include rule, only the articles it matches are kept.
+		{"allowlist matched", []paperboy.Rule{{Action: "include", Title: "Storm"}}, article("Storm", 100), true},
+		{"allowlist unmatched", []paperboy.Rule{{Action: "include", Title: "Storm"}}, article("Budget", 100), false},
+
+		// The first matching rule decides.
+		{"include before exclude", []paperboy.Rule{
+			{Action: "include", Title: "Storm"},
+			{Action: "exclude", Section: "world"},
+		}, article("Storm", 100), true},
+		{"exclude before include", []paperboy.Rule{
+			{Action: "exclude", Section: "world"},
+			{Action: "include", Title: "Storm"},
+		}, article("Storm", 100), false},
+
+		// Every condition of a rule must match.
+		{"all conditions", []paperboy.Rule{{Action: "exclude", Title: "Storm", Section: "sport"}}, article("Storm", 100), true},
+
+		{"exclude short", []paperboy.Rule{{Action: "exclude", MaxWords: 49}}, article("Brief", 10), false},
+		{"min words at", []paperboy.Rule{{Action: "include", MinWords: 10}}, article("Brief", 10), true},
+		{"min words above", []paperboy.Rule{{Action: "include", MinWords: 11}}, article("Brief", 10), false},
+		{"max words at", []paperboy.Rule{{Action: "include", MaxWords: 10}}, article("Brief", 10), true},
+		{"max words above", []paperboy.Rule{{Action: "include", MaxWords: 9}}, article("Brief", 10), false},
+		{"word range", []paperboy.Rule{{Action: "include", MinWords: 5, MaxWords: 20}}, article("Brief", 10), true},
+		{"word count field", []paperboy.Rule{{Action: "include", MinWords: 500}}, func() *paperboy.Result {
+			r := article("Long", 10)
+			r.Fields.WordCount = "800"
+			return r
+		}(), true},
+
+		{"tag id", []paperboy.Rule{{Action: "exclude", Tag: "^tone/obituaries$"}}, article("Obituary", 100, obituary), false},
+		{"tag title", []paperboy.Rule{{Action: "exclude", Tag: "^Obituaries$"}}, article("Obituary", 100, obituary), false},
+		{"tag unmatched", []paperboy.Rule{{Action: "exclude", Tag: "^tone/reviews$"}}, article("Obituary", 100, obituary), true},
+
+		// Authors are only matched against contributor tags.
+		{"author", []paperboy.Rule{{Action: "include", Author: "Jane"}}, article("Storm", 100, doe), true},
+		{"author not contributor", []paperboy.Rule{{Action: "include", Author: "John"}}, article("Storm", 100, roe), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.Keep(tt.r); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFilterInvalid(t *testing.T) {
+	for _, r := range []paperboy.Rule{
+		{Action: "drop"},
+		{Action: "include", Title: "("},
+		{Action: "include", MinWords: 10, MaxWords: 5},
+	} {
+		if _, err := NewFilter([]paperboy.Rule{r}); err == nil {
+			t.Errorf("%+v: expected an error", r)
+		}
+	}
+}
@@ -1,37 +1,94 @@
 package guardian
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"paperboy-back"
-	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/algao1/basically/btrank"
-	"github.com/algao1/basically/document"
-	"github.com/algao1/basically/parser"
-	"github.com/algao1/basically/trank"
 )
 
-// Service represents an implementation of paperboy.GuardianService.
+// Service represents an implementation of paperboy.NewsSource.
+// Interval is the minimum time between requests, to respect the API's rate limit.
 type Service struct {
-	Key    string
-	parser *parser.Parser
+	Key      string
+	Interval time.Duration
+
+	client *http.Client
+	mu     sync.Mutex
+	last   time.Time
 }
 
-var _ paperboy.GuardianService = (*Service)(nil)
+var _ paperboy.NewsSource = (*Service)(nil)
 
-// Create initializes the service with a key, and a parser.
+// Create initializes the service with a key.
 func Create(key string) *Service {
-	return &Service{Key: key, parser: parser.Create()}
+	return &Service{Key: key, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns the name of the source.
+func (s *Service) Name() string {
+	return "guardian"
 }
 
-// Fetch returns the result of querying the Guardian API with the specified parameters.
-func (s *Service) Fetch(qparams map[string]string) (*paperboy.Guardian, error) {
+// Fetch returns the articles from the Guardian API matching the query, walking through
+// the pages of results until none are left, or q.MaxPages pages have been fetched.
+func (s *Service) Fetch(ctx context.Context, q paperboy.Query) ([]*paperboy.Result, error) {
+	qparams := map[string]string{
+		"type":        "article",
+		"show-fields": "trailText,wordcount,bodyText",
+		"show-tags":   "contributor,tone",
+		"show-blocks": "main",
+	}
+	if q.Section != "" {
+		qparams["section"] = q.Section
+	}
+	if q.PageSize > 0 {
+		qparams["page-size"] = strconv.Itoa(q.PageSize)
+	}
+	if !q.From.IsZero() {
+		// Oldest first, so that capped pages never skip over older articles.
+		qparams["from-date"] = q.From.UTC().Format("2006-01-02T15:04:05.999999")
+		qparams["order-by"] = "oldest"
+	}
+	if !q.To.IsZero() {
+		qparams["to-date"] = q.To.UTC().Format("2006-01-02T15:04:05.999999")
+	}
+	for k, v := range q.Params {
+		qparams[k] = v
+	}
+
+	res := make([]*paperboy.Result, 0)
+	for page := 1; ; page++ {
+		qparams["page"] = strconv.Itoa(page)
+
+		g, err := s.Search(ctx, qparams)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "failed to fetch page "+qparams["page"], err)
+		}
+		res = append(res, g.Response.Results...)
+
+		if page >= g.Response.Pages {
+			break
+		}
+		if page >= q.MaxPages {
+			log.Printf("[Guardian API - %s] stopped after %d of %d pages (%d of %d results)\n",
+				strings.Title(q.Section), page, g.Response.Pages, len(res), g.Response.Total)
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// Search returns the result of querying the Guardian API with the specified parameters.
+func (s *Service) Search(ctx context.Context, qparams map[string]string) (*paperboy.Guardian, error) {
 	// Appends params onto url.
 	url := "https://content.guardianapis.com/search?api-key=" + s.Key
 	section := "all"
@@ -45,8 +102,15 @@ func (s *Service) Fetch(qparams map[string]string) (*paperboy.Guardian, error) {
 
 	section = strings.Title(section)
 
-	// Sends a GET request to url.
-	res, err := http.DefaultClient.Get(url)
+	// Sends a GET request to url, once the interval has passed.
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "invalid Guardian API request", err)
+	}
+	res, err := s.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("%q: %w", "getting Guardian API failed", err)
 	}
@@ -75,102 +139,21 @@ func (s *Service) Fetch(qparams map[string]string) (*paperboy.Guardian, error) {
 	return &g, nil
 }
 
-// Extract returns a slice of summaries corresponding with the given slice of paperboy.Result.
-func (s *Service) Extract(res []*paperboy.Result) ([]*paperboy.Summary, error) {
-	var summaries []*paperboy.Summary
-	for _, r := range res {
-		summ, err := s.ExtractOne(r)
-		if err != nil {
-			log.Printf("%v", err)
-			continue
-		}
-		summaries = append(summaries, summ)
-	}
-	return summaries, nil
-}
-
-// ExtractOne returns the result of summarizing a paperboy.Result.
-func (s *Service) ExtractOne(r *paperboy.Result) (*paperboy.Summary, error) {
-	// Find image and add the appropriate caption.
-	var im paperboy.Image
-	var assets []paperboy.Asset
-	if len(r.Blocks.Main.Elements) > 0 && r.Blocks.Main.Elements[0].Type == "image" {
-		assets = r.Blocks.Main.Elements[0].Assets
-		im = paperboy.Image{Caption: r.Blocks.Main.Elements[0].ImgData.Caption}
-	}
-	for _, a := range assets {
-		if a.TypeData.Width == 1000 {
-			// log.Printf("[%s] image found\n", r.Title)
-			im.ImageFileURL = a.File
-			break
-		}
-	}
-
-	// Find and convert date string to time.Time.
-	const layout = "2006-01-02T15:04:05Z"
-	date, err := time.Parse(layout, r.Date)
-	if err != nil {
-		return nil, fmt.Errorf("[%s] could not parse date", r.Title)
-	}
-
-	// Get authors.
-	authors := make([]string, 0)
-	for _, tag := range r.Tags {
-		authors = append(authors, tag.Title)
-	}
+// wait blocks until the interval has passed since the previous request, or ctx is done.
+func (s *Service) wait(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Eliminate HTML tags from TrailText.
-	reg := regexp.MustCompile("<[^>]*>")
-	tText := reg.ReplaceAllString(r.Fields.TrailText, "")
+	if d := time.Until(s.last.Add(s.Interval)); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
 
-	// Set up basically document.
-	doc, err := document.Create(r.Fields.BodyText, &btrank.BiasedTextRank{}, &trank.KWTextRank{}, s.parser)
-	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to create basically document", err)
-	}
-
-	// Summarization using basically.
-	sents, err := doc.Summarize(7, 0, "")
-	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to summarize document", err)
-	}
-	psents := make([]*paperboy.Sentence, len(sents))
-	for idx, sen := range sents {
-		psents[idx] = &paperboy.Sentence{Sentence: sen.Raw, Sentiment: sen.Sentiment}
-	}
-
-	// Keyword extraction using basically.
-	kwords, err := doc.Highlight(10, true)
-	if err != nil {
-		return nil, fmt.Errorf("%q: %w", "unable to extract keywords", err)
-	}
-	pkwords := make([]*paperboy.Keyword, len(kwords))
-	for idx, w := range kwords {
-		pkwords[idx] = &paperboy.Keyword{Word: w.Word, Weight: w.Weight}
-	}
-
-	fLength, sLength := doc.Characters()
-
-	summ := paperboy.Summary{
-		Info: paperboy.Info{
-			ContentID:   r.ContentID,
-			SectionID:   r.SectionID,
-			SectionName: r.SectionName,
-			URL:         r.URL,
-			Authors:     authors,
-			Date:        date,
-		},
-		Article: paperboy.Article{
-			Title:       r.Title,
-			TrailText:   tText,
-			SummaryText: psents,
-			Keywords:    pkwords,
-			FullLength:  fLength,
-			SummLength:  sLength,
-		},
-		Image: im,
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
-	// log.Printf("[%s] summarization complete\n", r.Title)
-
-	return &summ, nil
+	s.last = time.Now()
+	return nil
 }
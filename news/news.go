@@ -0,0 +1,103 @@
+// Package news contains the functionality shared by the news sources.
+package news
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"paperboy-back"
+	"regexp"
+	"time"
+)
+
+// DateLayout is the layout of paperboy.Result dates, which are always in UTC.
+const DateLayout = "2006-01-02T15:04:05Z"
+
+// Hash returns the hash of the article's body text, used to detect changes to the article.
+func Hash(r *paperboy.Result) string {
+	sum := sha256.Sum256([]byte(r.Fields.BodyText))
+	return hex.EncodeToString(sum[:])
+}
+
+// Extract returns the result of summarizing a paperboy.Result using the summarizer.
+// It is shared by every news source, as results are normalized beforehand.
+func Extract(ctx context.Context, sz paperboy.Summarizer, r *paperboy.Result, opts paperboy.SummarizeOptions) (*paperboy.Summary, error) {
+	summ, err := Metadata(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Summarization using the summarizer.
+	art, err := sz.Summarize(ctx, r.Fields.BodyText, opts)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", r.Title, err)
+	}
+	summ.Article.SummaryText = art.SummaryText
+	summ.Article.Keywords = art.Keywords
+	summ.Article.FullLength = art.FullLength
+	summ.Article.SummLength = art.SummLength
+	// log.Printf("[%s] summarization complete\n", r.Title)
+
+	return summ, nil
+}
+
+// Metadata returns the summary of a paperboy.Result without summarizing its body text,
+// containing only the article's metadata, image, and hash.
+func Metadata(r *paperboy.Result) (*paperboy.Summary, error) {
+	// Find image and add the appropriate caption.
+	var im paperboy.Image
+	var assets []paperboy.Asset
+	if len(r.Blocks.Main.Elements) > 0 && r.Blocks.Main.Elements[0].Type == "image" {
+		assets = r.Blocks.Main.Elements[0].Assets
+		im = paperboy.Image{Caption: r.Blocks.Main.Elements[0].ImgData.Caption}
+	}
+	for _, a := range assets {
+		if a.TypeData.Width == 1000 {
+			// log.Printf("[%s] image found\n", r.Title)
+			im.ImageFileURL = a.File
+			break
+		}
+	}
+	if im.ImageFileURL == "" && len(assets) == 1 {
+		im.ImageFileURL = assets[0].File
+	}
+
+	// Find and convert date string to time.Time.
+	date, err := time.Parse(DateLayout, r.Date)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] could not parse date", r.Title)
+	}
+
+	// Get authors, other tags are used by the filter rules.
+	authors := make([]string, 0)
+	for _, tag := range r.Tags {
+		if tag.Type == "contributor" {
+			authors = append(authors, tag.Title)
+		}
+	}
+
+	// Eliminate HTML tags from TrailText.
+	reg := regexp.MustCompile("<[^>]*>")
+	tText := reg.ReplaceAllString(r.Fields.TrailText, "")
+
+	summ := paperboy.Summary{
+		Info: paperboy.Info{
+			ContentID:   r.ContentID,
+			SectionID:   r.SectionID,
+			SectionName: r.SectionName,
+			URL:         r.URL,
+			Authors:     authors,
+			Date:        date,
+		},
+		Article: paperboy.Article{
+			Title:     r.Title,
+			TrailText: tText,
+		},
+		Image:   im,
+		Hash:    Hash(r),
+		SimHash: SimHash(r.Fields.BodyText),
+	}
+
+	return &summ, nil
+}
@@ -0,0 +1,370 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"paperboy-back"
+	"paperboy-back/news"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Feed is an RSS 2.0 or Atom feed, and the section its articles belong to.
+type Feed struct {
+	URL     string
+	Section string
+}
+
+// Source represents an implementation of paperboy.NewsSource for RSS and Atom feeds.
+type Source struct {
+	Feeds  []Feed
+	client *http.Client
+}
+
+var _ paperboy.NewsSource = (*Source)(nil)
+
+// Create initializes the source with the given feeds.
+func Create(feeds ...Feed) *Source {
+	return &Source{
+		Feeds:  feeds,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the name of the source.
+func (s *Source) Name() string {
+	return "rss"
+}
+
+// Fetch returns the articles from the feeds in the queried section, published between q.From and q.To.
+// If the query param 'url' is given, only that feed is fetched.
+func (s *Source) Fetch(ctx context.Context, q paperboy.Query) ([]*paperboy.Result, error) {
+	feeds := make([]Feed, 0)
+	if url, ok := q.Params["url"]; ok {
+		feeds = append(feeds, Feed{URL: url, Section: q.Section})
+	} else {
+		for _, f := range s.Feeds {
+			if q.Section == "" || f.Section == q.Section {
+				feeds = append(feeds, f)
+			}
+		}
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("no feeds found for section %q", q.Section)
+	}
+
+	// A broken feed should not prevent the others from being read.
+	var lastErr error
+	fetched := 0
+	res := make([]*paperboy.Result, 0)
+	for _, f := range feeds {
+		fres, err := s.fetchFeed(ctx, f)
+		if err != nil {
+			log.Printf("[RSS - %s] %v\n", f.URL, err)
+			lastErr = err
+			continue
+		}
+		fetched++
+
+		for _, r := range fres {
+			date, err := time.Parse(news.DateLayout, r.Date)
+			if err == nil && (date.Before(q.From) || !q.To.IsZero() && date.After(q.To)) {
+				continue
+			}
+			res = append(res, r)
+		}
+	}
+	if fetched == 0 {
+		return nil, fmt.Errorf("%q: %w", "unable to fetch feeds", lastErr)
+	}
+
+	// Oldest first, so that a capped page never skips over older articles, which would be
+	// left behind the high-water mark. Dates share a layout, so they sort as strings.
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Date < res[j].Date })
+	if q.PageSize > 0 && len(res) > q.PageSize {
+		res = res[:q.PageSize]
+	}
+	return res, nil
+}
+
+// fetchFeed downloads and parses a single feed.
+func (s *Source) fetchFeed(ctx context.Context, f Feed) ([]*paperboy.Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "invalid feed url", err)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "getting feed failed", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("getting feed failed: %s", res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "failed to read feed body", err)
+	}
+
+	items, err := parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*paperboy.Result, 0, len(items))
+	for _, it := range items {
+		r, err := it.result(f.Section)
+		if err != nil {
+			log.Printf("[RSS - %s] %v\n", f.URL, err)
+			continue
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// item is an entry of either an RSS 2.0 or Atom feed.
+type item struct {
+	ID      string
+	Title   string
+	Link    string
+	Date    string
+	Summary string
+	Content string
+	Authors []string
+	Image   string
+}
+
+// result normalizes the item into a paperboy.Result.
+func (it *item) result(section string) (*paperboy.Result, error) {
+	date, err := parseDate(it.Date)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] could not parse date %q", it.Title, it.Date)
+	}
+
+	// Prefer the full content, and fall back to the summary.
+	body := text(it.Content)
+	if body == "" {
+		body = text(it.Summary)
+	}
+
+	id := it.Link
+	if id == "" {
+		id = it.ID
+	}
+
+	tags := make([]paperboy.Tag, len(it.Authors))
+	for idx, a := range it.Authors {
+		tags[idx] = paperboy.Tag{Type: "contributor", Title: a}
+	}
+
+	var blocks paperboy.Blocks
+	if it.Image != "" {
+		blocks.Main.Elements = []paperboy.Element{{
+			Type:   "image",
+			Assets: []paperboy.Asset{{File: it.Image}},
+		}}
+	}
+
+	return &paperboy.Result{
+		ContentID:   id,
+		SectionID:   section,
+		SectionName: strings.Title(section),
+		URL:         it.Link,
+		Date:        date.UTC().Format(news.DateLayout),
+		Title:       html.UnescapeString(strings.TrimSpace(it.Title)),
+		Fields: paperboy.Fields{
+			TrailText: text(it.Summary),
+			BodyText:  body,
+			WordCount: strconv.Itoa(len(strings.Fields(body))),
+		},
+		Tags:   tags,
+		Blocks: blocks,
+	}, nil
+}
+
+type rssFeed struct {
+	Items []struct {
+		GUID        string `xml:"guid"`
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		PubDate     string `xml:"pubDate"`
+		Description string `xml:"description"`
+		Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		DCDate      string `xml:"http://purl.org/dc/elements/1.1/ date"`
+		Author      string `xml:"author"`
+		Enclosure   struct {
+			URL  string `xml:"url,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"enclosure"`
+		Media []struct {
+			URL    string `xml:"url,attr"`
+			Medium string `xml:"medium,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ content"`
+	} `xml:"channel>item"`
+}
+
+type atomFeed struct {
+	Entries []struct {
+		ID        string `xml:"id"`
+		Title     string `xml:"title"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Links     []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+		Authors []struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+	} `xml:"entry"`
+}
+
+// parse returns the items of an RSS 2.0 or Atom feed, depending on its root element.
+func parse(body []byte) ([]*item, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+
+	var root string
+	for root == "" {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to read feed", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			root = se.Name.Local
+		}
+	}
+
+	dec = xml.NewDecoder(bytes.NewReader(body))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+
+	switch root {
+	case "rss":
+		var f rssFeed
+		if err := dec.Decode(&f); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode rss feed", err)
+		}
+
+		items := make([]*item, len(f.Items))
+		for idx, i := range f.Items {
+			it := &item{
+				ID:      i.GUID,
+				Title:   i.Title,
+				Link:    strings.TrimSpace(i.Link),
+				Date:    i.PubDate,
+				Summary: i.Description,
+				Content: i.Content,
+			}
+			if it.Date == "" {
+				it.Date = i.DCDate
+			}
+			if i.Creator != "" {
+				it.Authors = append(it.Authors, strings.TrimSpace(i.Creator))
+			} else if i.Author != "" {
+				it.Authors = append(it.Authors, strings.TrimSpace(i.Author))
+			}
+			for _, m := range i.Media {
+				if m.Medium == "" || m.Medium == "image" {
+					it.Image = m.URL
+					break
+				}
+			}
+			if it.Image == "" && strings.HasPrefix(i.Enclosure.Type, "image/") {
+				it.Image = i.Enclosure.URL
+			}
+			items[idx] = it
+		}
+		return items, nil
+	case "feed":
+		var f atomFeed
+		if err := dec.Decode(&f); err != nil {
+			return nil, fmt.Errorf("%q: %w", "unable to decode atom feed", err)
+		}
+
+		items := make([]*item, len(f.Entries))
+		for idx, e := range f.Entries {
+			it := &item{
+				ID:      e.ID,
+				Title:   e.Title,
+				Date:    e.Published,
+				Summary: e.Summary,
+				Content: e.Content,
+			}
+			if it.Date == "" {
+				it.Date = e.Updated
+			}
+			for _, a := range e.Authors {
+				it.Authors = append(it.Authors, strings.TrimSpace(a.Name))
+			}
+			for _, l := range e.Links {
+				switch {
+				case (l.Rel == "" || l.Rel == "alternate") && it.Link == "":
+					it.Link = l.Href
+				case l.Rel == "enclosure" && strings.HasPrefix(l.Type, "image/") && it.Image == "":
+					it.Image = l.Href
+				}
+			}
+			items[idx] = it
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("unknown feed format %q", root)
+}
+
+// Layouts of the dates found in feeds, RSS uses RFC 822 and Atom uses RFC 3339.
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format %q", s)
+}
+
+var (
+	blockReg = regexp.MustCompile(`(?i)<(br|/p|/div|/h[1-6]|/li|/blockquote)[^>]*>`)
+	tagReg   = regexp.MustCompile("<[^>]*>")
+	spaceReg = regexp.MustCompile(`[ \t\r\f\v]+`)
+	lineReg  = regexp.MustCompile(`\s*\n\s*`)
+)
+
+// text returns the plain text of an HTML fragment, keeping paragraph breaks.
+func text(s string) string {
+	s = blockReg.ReplaceAllString(s, "\n")
+	s = tagReg.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = spaceReg.ReplaceAllString(s, " ")
+	s = lineReg.ReplaceAllString(s, "\n")
+	return strings.TrimSpace(s)
+}
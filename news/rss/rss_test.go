@@ -0,0 +1,210 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"paperboy-back"
+	"testing"
+	"time"
+)
+
+// serve returns a server of the feeds in testdata.
+func serve(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// byID returns the results keyed by contentID.
+func byID(res []*paperboy.Result) map[string]*paperboy.Result {
+	m := make(map[string]*paperboy.Result, len(res))
+	for _, r := range res {
+		m[r.ContentID] = r
+	}
+	return m
+}
+
+func TestFetchRSS(t *testing.T) {
+	srv := serve(t)
+	s := Create(Feed{URL: srv.URL + "/rss.xml", Section: "world"})
+
+	res, err := s.Fetch(context.Background(), paperboy.Query{Section: "world"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The undated item is skipped.
+	if len(res) != 3 {
+		t.Fatalf("got %d results, want 3", len(res))
+	}
+
+	r := byID(res)["https://news.example.com/storm"]
+	if r == nil || r.URL != r.ContentID {
+		t.Fatalf("storm: got %+v", r)
+	}
+	if r.Title != "Storm closes schools & roads" {
+		t.Errorf("title: got %q", r.Title)
+	}
+	if r.SectionID != "world" || r.SectionName != "World" {
+		t.Errorf("section: got %q, %q", r.SectionID, r.SectionName)
+	}
+	if r.Fields.TrailText != "Schools were closed across the region." {
+		t.Errorf("trail text: got %q", r.Fields.TrailText)
+	}
+	if r.Fields.BodyText != "Schools were closed across the region.\nSeveral roads were flooded." {
+		t.Errorf("body: got %q", r.Fields.BodyText)
+	}
+	if len(r.Tags) != 1 || r.Tags[0].Title != "Jane Doe" {
+		t.Errorf("authors: got %+v", r.Tags)
+	}
+	if els := r.Blocks.Main.Elements; len(els) != 1 || els[0].Assets[0].File != "https://news.example.com/storm.jpg" {
+		t.Errorf("image: got %+v", els)
+	}
+
+	// Without content, the body falls back to the description.
+	b := byID(res)["https://news.example.com/budget"]
+	if b.Fields.BodyText != "The budget passed with a narrow majority." {
+		t.Errorf("body: got %q", b.Fields.BodyText)
+	}
+	if els := b.Blocks.Main.Elements; len(els) != 1 || els[0].Assets[0].File != "https://news.example.com/budget.jpg" {
+		t.Errorf("enclosure: got %+v", els)
+	}
+}
+
+func TestFetchAtom(t *testing.T) {
+	srv := serve(t)
+	s := Create(Feed{URL: srv.URL + "/atom.xml", Section: "science"})
+
+	res, err := s.Fetch(context.Background(), paperboy.Query{Section: "science"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("got %d results, want 2", len(res))
+	}
+
+	r := byID(res)["https://science.example.com/comet"]
+	if r == nil {
+		t.Fatalf("comet: not found in %v", res)
+	}
+	if r.Title != "Comet visible this weekend" {
+		t.Errorf("title: got %q", r.Title)
+	}
+	if r.Fields.BodyText != "A bright comet will be visible to the naked eye." {
+		t.Errorf("body: got %q", r.Fields.BodyText)
+	}
+	if len(r.Tags) != 2 || r.Tags[0].Title != "Ada Lovelace" || r.Tags[1].Title != "Carl Sagan" {
+		t.Errorf("authors: got %+v", r.Tags)
+	}
+	if els := r.Blocks.Main.Elements; len(els) != 1 || els[0].Assets[0].File != "https://science.example.com/comet.png" {
+		t.Errorf("image: got %+v", els)
+	}
+
+	// A link without rel is the alternate link.
+	if byID(res)["https://science.example.com/fossil"] == nil {
+		t.Errorf("fossil: not found in %v", res)
+	}
+}
+
+func TestDates(t *testing.T) {
+	srv := serve(t)
+	s := Create(
+		Feed{URL: srv.URL + "/rss.xml", Section: "news"},
+		Feed{URL: srv.URL + "/atom.xml", Section: "news"},
+	)
+
+	res, err := s.Fetch(context.Background(), paperboy.Query{Section: "news"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		// RFC 1123 with a numeric zone.
+		"https://news.example.com/storm": "2021-03-04T09:30:00Z",
+		// RFC 822 with a single digit day, and a named zone.
+		"https://news.example.com/budget": "2021-03-02T18:00:00Z",
+		// Dublin Core date, in RFC 3339.
+		"https://news.example.com/market": "2021-03-01T08:00:00Z",
+		// RFC 3339 with an offset, converted to UTC.
+		"https://science.example.com/comet": "2021-03-05T09:15:00Z",
+		// RFC 3339 with fractional seconds, from the updated date.
+		"https://science.example.com/fossil": "2021-02-20T09:00:00Z",
+	}
+	if len(res) != len(want) {
+		t.Fatalf("got %d results, want %d", len(res), len(want))
+	}
+	for _, r := range res {
+		if r.Date != want[r.ContentID] {
+			t.Errorf("%s: got date %q, want %q", r.ContentID, r.Date, want[r.ContentID])
+		}
+	}
+}
+
+func TestFetchFilters(t *testing.T) {
+	srv := serve(t)
+	s := Create(
+		Feed{URL: srv.URL + "/rss.xml", Section: "news"},
+		Feed{URL: srv.URL + "/atom.xml", Section: "news"},
+		Feed{URL: srv.URL + "/missing.xml", Section: "news"},
+	)
+	day := func(d int) time.Time {
+		return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		q    paperboy.Query
+		want int
+	}{
+		{"all", paperboy.Query{}, 5},
+		{"from", paperboy.Query{From: day(3)}, 2},
+		{"to", paperboy.Query{To: day(2)}, 2},
+		{"between", paperboy.Query{From: day(2), To: day(5)}, 2},
+		{"none", paperboy.Query{From: day(10)}, 0},
+		{"page size", paperboy.Query{PageSize: 1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.q.Section = "news"
+			res, err := s.Fetch(context.Background(), tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res) != tt.want {
+				t.Fatalf("got %d results, want %d", len(res), tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchNoFeeds(t *testing.T) {
+	srv := serve(t)
+	s := Create(Feed{URL: srv.URL + "/missing.xml", Section: "news"})
+
+	if _, err := s.Fetch(context.Background(), paperboy.Query{Section: "news"}); err == nil {
+		t.Fatal("expected error when no feed can be fetched")
+	}
+	if _, err := s.Fetch(context.Background(), paperboy.Query{Section: "sport"}); err == nil {
+		t.Fatal("expected error when no feed is in the section")
+	}
+}
+
+func TestFetchOldestFirst(t *testing.T) {
+	srv := serve(t)
+	s := Create(
+		Feed{URL: srv.URL + "/rss.xml", Section: "news"},
+		Feed{URL: srv.URL + "/atom.xml", Section: "news"},
+	)
+
+	// The feeds list their items newest first, the page keeps the oldest.
+	res, err := s.Fetch(context.Background(), paperboy.Query{Section: "news", PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{res[0].ContentID, res[1].ContentID}
+	want := []string{"https://science.example.com/fossil", "https://news.example.com/market"}
+	if got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
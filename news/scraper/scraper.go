@@ -0,0 +1,419 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"paperboy-back"
+	"paperboy-back/news"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Page is an article page to be scraped, and the section it belongs to.
+type Page struct {
+	URL     string
+	Section string
+}
+
+// Source represents an implementation of paperboy.NewsSource that scrapes article pages.
+type Source struct {
+	Pages  []Page
+	client *http.Client
+}
+
+var _ paperboy.NewsSource = (*Source)(nil)
+
+// Create initializes the source with the given pages.
+func Create(pages ...Page) *Source {
+	return &Source{
+		Pages:  pages,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the name of the source.
+func (s *Source) Name() string {
+	return "scraper"
+}
+
+// Fetch returns the scraped articles from the pages in the queried section, published between q.From and q.To.
+// If the query param 'urls' is given, the comma-separated pages are scraped instead.
+func (s *Source) Fetch(ctx context.Context, q paperboy.Query) ([]*paperboy.Result, error) {
+	pages := make([]Page, 0)
+	if urls, ok := q.Params["urls"]; ok {
+		for _, u := range strings.Split(urls, ",") {
+			pages = append(pages, Page{URL: strings.TrimSpace(u), Section: q.Section})
+		}
+	} else {
+		for _, p := range s.Pages {
+			if q.Section == "" || p.Section == q.Section {
+				pages = append(pages, p)
+			}
+		}
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found for section %q", q.Section)
+	}
+
+	// A broken page should not prevent the others from being scraped.
+	var lastErr error
+	scraped := 0
+	res := make([]*paperboy.Result, 0)
+	for _, p := range pages {
+		r, err := s.Scrape(ctx, p)
+		if err != nil {
+			log.Printf("[Scraper - %s] %v\n", p.URL, err)
+			lastErr = err
+			continue
+		}
+		scraped++
+
+		date, err := time.Parse(news.DateLayout, r.Date)
+		if err == nil && (date.Before(q.From) || !q.To.IsZero() && date.After(q.To)) {
+			continue
+		}
+		res = append(res, r)
+	}
+	if scraped == 0 && lastErr != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to scrape pages", lastErr)
+	}
+
+	// Oldest first, so that a capped page never skips over older articles, which would be
+	// left behind the high-water mark. Dates share a layout, so they sort as strings.
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Date < res[j].Date })
+	if q.PageSize > 0 && len(res) > q.PageSize {
+		res = res[:q.PageSize]
+	}
+	return res, nil
+}
+
+// Scrape downloads the page, and extracts the article into a paperboy.Result.
+func (s *Source) Scrape(ctx context.Context, p Page) (*paperboy.Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "invalid page url", err)
+	}
+	req.Header.Set("User-Agent", "paperboy/1.0 (+http://paperboynews.ca)")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "getting page failed", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("getting page failed: %s", res.Status)
+	}
+
+	doc, err := html.Parse(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to parse page", err)
+	}
+
+	a := extract(doc)
+	if a.Body == "" {
+		return nil, fmt.Errorf("no article body found")
+	}
+
+	// Fall back to the time the page was last modified. Pages without either are skipped,
+	// as they would be dated anew, and brought back to the top of their section, every run.
+	date, err := parseDate(a.Date)
+	if err != nil {
+		date, err = http.ParseTime(res.Header.Get("Last-Modified"))
+		if err != nil {
+			return nil, fmt.Errorf("no publication date found")
+		}
+	}
+
+	var tags []paperboy.Tag
+	if a.Author != "" {
+		tags = append(tags, paperboy.Tag{Type: "contributor", Title: a.Author})
+	}
+
+	var blocks paperboy.Blocks
+	if a.Image != "" {
+		blocks.Main.Elements = []paperboy.Element{{
+			Type:   "image",
+			Assets: []paperboy.Asset{{File: resolve(res.Request.URL, a.Image)}},
+		}}
+	}
+
+	return &paperboy.Result{
+		ContentID:   p.URL,
+		SectionID:   p.Section,
+		SectionName: strings.Title(p.Section),
+		URL:         p.URL,
+		Date:        date.UTC().Format(news.DateLayout),
+		Title:       a.Title,
+		Fields: paperboy.Fields{
+			TrailText: a.Description,
+			BodyText:  a.Body,
+			WordCount: strconv.Itoa(len(strings.Fields(a.Body))),
+		},
+		Tags:   tags,
+		Blocks: blocks,
+	}, nil
+}
+
+// article contains the information extracted from a page.
+type article struct {
+	Title       string
+	Description string
+	Author      string
+	Date        string
+	Image       string
+	Body        string
+}
+
+// extract returns the article found within the document. Metadata is taken from
+// the OpenGraph and article meta tags, and the body from the best scoring container.
+func extract(doc *html.Node) *article {
+	var a article
+	var title, h1 string
+
+	walk(doc, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.Meta:
+			key := attr(n, "property")
+			if key == "" {
+				key = attr(n, "name")
+			}
+			val := strings.TrimSpace(attr(n, "content"))
+			if val == "" {
+				return true
+			}
+
+			switch strings.ToLower(key) {
+			case "og:title", "twitter:title":
+				setOnce(&a.Title, val)
+			case "og:description", "description", "twitter:description":
+				setOnce(&a.Description, val)
+			case "author", "article:author", "byl", "parsely-author":
+				setOnce(&a.Author, strings.TrimPrefix(val, "By "))
+			case "article:published_time", "date", "pubdate", "publishdate", "dc.date.issued":
+				setOnce(&a.Date, val)
+			case "og:image", "twitter:image":
+				setOnce(&a.Image, val)
+			}
+		case atom.Time:
+			setOnce(&a.Date, attr(n, "datetime"))
+		case atom.Title:
+			setOnce(&title, textContent(n))
+		case atom.H1:
+			setOnce(&h1, textContent(n))
+		case atom.A:
+			if attr(n, "rel") == "author" {
+				setOnce(&a.Author, textContent(n))
+			}
+		}
+		return true
+	})
+
+	setOnce(&a.Title, h1)
+	setOnce(&a.Title, title)
+	a.Body = body(doc)
+
+	return &a
+}
+
+// Elements that never contain the main content of an article.
+var boilerplate = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Button:   true,
+	atom.Svg:      true,
+	atom.Figure:   true,
+}
+
+// Class and id hints used to adjust the score of a container.
+var (
+	negative = []string{"comment", "footer", "sidebar", "related", "share", "social", "promo", "advert", "newsletter", "nav", "menu", "cookie", "subscribe"}
+	positive = []string{"article", "body", "content", "entry", "main", "post", "story", "text"}
+)
+
+// body returns the main text of the document, readability-style. Each paragraph scores its
+// parent and grandparent by its length and commas, and the best container, once penalized
+// by link density and class hints, holds the article body.
+func body(doc *html.Node) string {
+	scores := make(map[*html.Node]float64)
+
+	walk(doc, func(n *html.Node) bool {
+		if boilerplate[n.DataAtom] {
+			return false
+		}
+		if n.DataAtom != atom.P {
+			return true
+		}
+
+		t := textContent(n)
+		if len(t) < 25 || n.Parent == nil {
+			return false
+		}
+
+		score := 1 + float64(strings.Count(t, ",")) + math.Min(float64(len(t))/100, 3)
+		scores[n.Parent] += score
+		if n.Parent.Parent != nil {
+			scores[n.Parent.Parent] += score / 2
+		}
+		return false
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	for n, score := range scores {
+		score *= (1 - linkDensity(n)) * (1 + hint(n))
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	if best == nil {
+		return ""
+	}
+
+	// Collect the paragraphs and headings within the container.
+	paras := make([]string, 0)
+	walk(best, func(n *html.Node) bool {
+		if boilerplate[n.DataAtom] {
+			return false
+		}
+		switch n.DataAtom {
+		case atom.P, atom.H2, atom.H3, atom.Li, atom.Blockquote:
+			if t := textContent(n); len(t) > 0 && linkDensity(n) < 0.5 {
+				paras = append(paras, t)
+			}
+			return false
+		}
+		return true
+	})
+
+	return strings.Join(paras, "\n")
+}
+
+// hint returns a score adjustment based on the class and id of a node.
+func hint(n *html.Node) float64 {
+	s := strings.ToLower(attr(n, "class") + " " + attr(n, "id"))
+	h := 0.0
+	for _, w := range negative {
+		if strings.Contains(s, w) {
+			h -= 0.25
+		}
+	}
+	for _, w := range positive {
+		if strings.Contains(s, w) {
+			h += 0.25
+		}
+	}
+	return math.Max(h, -0.75)
+}
+
+// linkDensity returns the fraction of the node's text found within links.
+func linkDensity(n *html.Node) float64 {
+	total := len(textContent(n))
+	if total == 0 {
+		return 0
+	}
+
+	links := 0
+	walk(n, func(c *html.Node) bool {
+		if c.DataAtom == atom.A {
+			links += len(textContent(c))
+			return false
+		}
+		return true
+	})
+	return float64(links) / float64(total)
+}
+
+// walk visits the node and its descendants in order, skipping the children
+// of any node for which fn returns false.
+func walk(n *html.Node, fn func(*html.Node) bool) {
+	if n.Type == html.ElementNode && !fn(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+// textContent returns the whitespace-normalized text of the node.
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+			return
+		}
+		if boilerplate[n.DataAtom] {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setOnce(dst *string, val string) {
+	if *dst == "" {
+		*dst = strings.TrimSpace(val)
+	}
+}
+
+// resolve returns the reference resolved against the base url.
+func resolve(base *url.URL, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// Layouts of the dates found in article meta tags.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format %q", s)
+}
@@ -0,0 +1,160 @@
+package scraper
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"paperboy-back"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve returns a server of the pages in testdata. The Last-Modified header is set from the
+// query param 'modified', so that pages are otherwise undated.
+func serve(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", filepath.Base(r.URL.Path)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if mod := r.URL.Query().Get("modified"); mod != "" {
+			w.Header().Set("Last-Modified", mod)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(b)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestScrape(t *testing.T) {
+	srv := serve(t)
+	s := Create()
+
+	r, err := s.Scrape(context.Background(), Page{URL: srv.URL + "/article.html", Section: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Title != "Council approves new cycle lanes" {
+		t.Errorf("title: got %q", r.Title)
+	}
+	if r.Fields.TrailText != "The lanes will connect the city centre to the university." {
+		t.Errorf("trail text: got %q", r.Fields.TrailText)
+	}
+	if len(r.Tags) != 1 || r.Tags[0].Type != "contributor" || r.Tags[0].Title != "Jane Doe" {
+		t.Errorf("author: got %+v", r.Tags)
+	}
+	if r.Date != "2021-03-04T09:30:00Z" {
+		t.Errorf("date: got %q", r.Date)
+	}
+	if r.SectionID != "local" || r.SectionName != "Local" {
+		t.Errorf("section: got %q, %q", r.SectionID, r.SectionName)
+	}
+
+	els := r.Blocks.Main.Elements
+	if len(els) != 1 || els[0].Type != "image" || len(els[0].Assets) != 1 ||
+		els[0].Assets[0].File != srv.URL+"/images/lanes.jpg" {
+		t.Errorf("image: got %+v", els)
+	}
+
+	for _, want := range []string{
+		"The city council has approved a plan",
+		"Councillors voted",
+		"Residents divided",
+		"Construction is expected to begin",
+	} {
+		if !strings.Contains(r.Fields.BodyText, want) {
+			t.Errorf("body: missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{
+		"Navigation paragraph",
+		"Related:",
+		"Comment:",
+		"Copyright",
+		"tracking",
+	} {
+		if strings.Contains(r.Fields.BodyText, unwanted) {
+			t.Errorf("body: contains boilerplate %q", unwanted)
+		}
+	}
+}
+
+func TestScrapeLastModified(t *testing.T) {
+	srv := serve(t)
+	s := Create()
+
+	mod := "Tue, 02 Mar 2021 10:00:00 GMT"
+	r, err := s.Scrape(context.Background(), Page{URL: srv.URL + "/undated.html?modified=" + strings.ReplaceAll(mod, " ", "%20")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Date != "2021-03-02T10:00:00Z" {
+		t.Errorf("date: got %q", r.Date)
+	}
+}
+
+func TestScrapeUndated(t *testing.T) {
+	srv := serve(t)
+	s := Create()
+
+	if _, err := s.Scrape(context.Background(), Page{URL: srv.URL + "/undated.html"}); err == nil {
+		t.Fatal("expected undated page to be skipped")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := serve(t)
+	s := Create(
+		Page{URL: srv.URL + "/article.html", Section: "local"},
+		Page{URL: srv.URL + "/undated.html", Section: "local"},
+		Page{URL: srv.URL + "/missing.html", Section: "local"},
+	)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{"all", time.Time{}, time.Time{}, 1},
+		{"within", time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC), 1},
+		{"after", time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC), time.Time{}, 0},
+		{"before", time.Time{}, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := paperboy.Query{Section: "local", From: tt.from, To: tt.to}
+			res, err := s.Fetch(context.Background(), q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res) != tt.want {
+				t.Fatalf("got %d results, want %d", len(res), tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchOldestFirst(t *testing.T) {
+	srv := serve(t)
+	older := srv.URL + "/undated.html?modified=Mon,%2001%20Mar%202021%2010:00:00%20GMT"
+	s := Create(
+		Page{URL: srv.URL + "/article.html", Section: "local"},
+		Page{URL: older, Section: "local"},
+	)
+
+	// The pages are listed newest first, the page keeps the oldest.
+	res, err := s.Fetch(context.Background(), paperboy.Query{Section: "local", PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].ContentID != older {
+		t.Fatalf("got %+v, want %s", res, older)
+	}
+}
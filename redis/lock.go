@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"paperboy-back"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Locker is an implementation of paperboy.Locker using Redis. Each lock is a key holding the
+// token of the replica which owns it, expiring with the lease.
+type Locker struct {
+	rdb   *redis.Client
+	token string
+}
+
+var _ paperboy.Locker = (*Locker)(nil)
+
+// Scripts which only modify a lock if it is still owned by the replica.
+var (
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// NewLocker returns a new Locker, whose locks are owned by this replica.
+func NewLocker(addr, port, pass string, db int) (*Locker, error) {
+	// Identifies the replica by its hostname, which is the pod name on Kubernetes.
+	host, _ := os.Hostname()
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to generate lock token", err)
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", addr, port),
+		Password: pass,
+		DB:       db,
+	})
+
+	return &Locker{rdb: rdb, token: host + "-" + hex.EncodeToString(b)}, nil
+}
+
+// Close closes the connection to Redis.
+func (l *Locker) Close() error {
+	return l.rdb.Close()
+}
+
+// Acquire takes the lock for ttl, unless it is held by another replica.
+func (l *Locker) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	ok, err := l.rdb.SetNX(ctx, key(name), l.token, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("%q: %w", "unable to acquire lock", err)
+	}
+	return ok, nil
+}
+
+// Renew extends the lease on the lock, if it is still held by this replica.
+func (l *Locker) Renew(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	n, err := renewScript.Run(ctx, l.rdb, []string{key(name)}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("%q: %w", "unable to renew lock", err)
+	}
+	return n == 1, nil
+}
+
+// Release releases the lock, if it is still held by this replica.
+func (l *Locker) Release(ctx context.Context, name string) error {
+	if err := releaseScript.Run(ctx, l.rdb, []string{key(name)}, l.token).Err(); err != nil {
+		return fmt.Errorf("%q: %w", "unable to release lock", err)
+	}
+	return nil
+}
+
+func key(name string) string {
+	return "paperboy:lock:" + name
+}
@@ -31,13 +31,18 @@ func NewSummaryCache(addr, port, pass string, db int, ss paperboy.SummaryService
 	return &Redis{rdb: rdb, ss: ss}
 }
 
+// Close closes the connection to Redis.
+func (r *Redis) Close() error {
+	return r.rdb.Close()
+}
+
 // Summary returns a pointer to a summary for a given objectID.
-func (r *Redis) Summary(objectID string) (*paperboy.Summary, error) {
-	ctx, cancel := context.WithTimeout(context.TODO(), 500*time.Millisecond)
+func (r *Redis) Summary(ctx context.Context, objectID string) (*paperboy.Summary, error) {
+	rctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
 	defer cancel()
 
 	// Checks the local cache before querying service.
-	s, _ := r.rdb.Get(ctx, objectID).Result()
+	s, _ := r.rdb.Get(rctx, objectID).Result()
 	if len(s) > 0 {
 		var ret paperboy.Summary
 		err := json.Unmarshal([]byte(s), &ret)
@@ -48,7 +53,7 @@ func (r *Redis) Summary(objectID string) (*paperboy.Summary, error) {
 	}
 
 	// Otherwise, fetch from the underlying service.
-	sum, err := r.ss.Summary(objectID)
+	sum, err := r.ss.Summary(ctx, objectID)
 	if err != nil {
 		return nil, fmt.Errorf("%q: %w", "unable to fetch from service", err)
 	} else if sum != nil {
@@ -56,7 +61,7 @@ func (r *Redis) Summary(objectID string) (*paperboy.Summary, error) {
 		if err != nil {
 			return nil, fmt.Errorf("%q: %w", "unable to unmarshal json", err)
 		}
-		r.rdb.Set(ctx, objectID, json, 1*time.Hour)
+		r.rdb.Set(rctx, objectID, json, 1*time.Hour)
 	}
 
 	return sum, nil
@@ -64,17 +69,17 @@ func (r *Redis) Summary(objectID string) (*paperboy.Summary, error) {
 
 // Summaries returns a slice of the most recent summaries with a given sectionID such as
 // 'world' or 'tech'. A limit must be set for the maximum number of documents fetched.
-func (r *Redis) Summaries(sectionID string, endDate time.Time, size int) ([]*paperboy.Summary, string, error) {
-	ctx, cancel := context.WithTimeout(context.TODO(), 500*time.Millisecond)
+func (r *Redis) Summaries(ctx context.Context, sectionID string, endDate time.Time, size int) ([]*paperboy.Summary, string, error) {
+	rctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
 	defer cancel()
 
 	// If we get zero-value for endDate, skip Redis and use current time.
 	if endDate.Equal(time.Time{}) {
-		return r.ss.Summaries(sectionID, time.Now(), size)
+		return r.ss.Summaries(ctx, sectionID, time.Now(), size)
 	}
 
 	// Checks the local cache before querying service.
-	sstr, err := r.rdb.Get(ctx, fmt.Sprintf("%s:%s:%v", sectionID, endDate, size)).Result()
+	sstr, err := r.rdb.Get(rctx, fmt.Sprintf("%s:%s:%v", sectionID, endDate, size)).Result()
 	if err == nil {
 		var ret paperboy.SummariesResponse
 		err := json.Unmarshal([]byte(sstr), &ret)
@@ -85,7 +90,7 @@ func (r *Redis) Summaries(sectionID string, endDate time.Time, size int) ([]*pap
 	}
 
 	// Otherwise, fetch from the underlying service.
-	sum, last, err := r.ss.Summaries(sectionID, endDate, size)
+	sum, last, err := r.ss.Summaries(ctx, sectionID, endDate, size)
 	if err != nil {
 		return nil, "", fmt.Errorf("%q: %w", "unable to retrieve summaries", err)
 	} else if sum != nil {
@@ -93,19 +98,29 @@ func (r *Redis) Summaries(sectionID string, endDate time.Time, size int) ([]*pap
 		if err != nil {
 			return nil, "", fmt.Errorf("%q: %w", "unable to unmarshal json", err)
 		}
-		r.rdb.Set(ctx, fmt.Sprintf("%s:%s:%v", sectionID, endDate, size), json, 1*time.Hour)
+		r.rdb.Set(rctx, fmt.Sprintf("%s:%s:%v", sectionID, endDate, size), json, 1*time.Hour)
 	}
 
 	return sum, last, nil
 }
 
 // Search returns a list of summaries matched by Mongo's fuzzy search.
-func (r *Redis) Search(query string, size int) ([]*paperboy.Summary, error) {
-	return r.ss.Search(query, size)
+func (r *Redis) Search(ctx context.Context, query string, size int) ([]*paperboy.Summary, error) {
+	return r.ss.Search(ctx, query, size)
 }
 
 // Create inserts a summary into the database if possible, otherwise,
 // it will update the existing entry.
-func (r *Redis) Create(s *paperboy.Summary) error {
-	return r.ss.Create(s)
+func (r *Redis) Create(ctx context.Context, s *paperboy.Summary) error {
+	return r.ss.Create(ctx, s)
+}
+
+// Hashes returns the hashes of the summaries with the given contentIDs.
+func (r *Redis) Hashes(ctx context.Context, contentIDs []string) (map[string]string, error) {
+	return r.ss.Hashes(ctx, contentIDs)
+}
+
+// UpdateInfo updates the metadata of an existing summary.
+func (r *Redis) UpdateInfo(ctx context.Context, s *paperboy.Summary) error {
+	return r.ss.UpdateInfo(ctx, s)
 }
@@ -0,0 +1,52 @@
+package paperboy
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Actions of a filter rule.
+const (
+	RuleInclude = "include"
+	RuleExclude = "exclude"
+)
+
+// Rule is a filter rule, matching the articles which satisfy all of its conditions. Each
+// pattern is a regular expression, and unset conditions match every article. A job's
+// rules are tried in order, and the first to match an article decides whether it is
+// included or excluded. Articles matching no rule are included, unless the job has
+// include rules, in which case only the articles they match are included.
+//
+//	Action: 'include' or 'exclude'.
+//	Title: matches the title.
+//	Section: matches the section id.
+//	Tag: matches the id or title of any tag, such as 'tone/obituaries'.
+//	Author: matches the name of any contributor.
+//	Body: matches the body text.
+//	MinWords, MaxWords: the range of the word count, inclusive.
+type Rule struct {
+	Action   string `yaml:"action"`
+	Title    string `yaml:"title"`
+	Section  string `yaml:"section"`
+	Tag      string `yaml:"tag"`
+	Author   string `yaml:"author"`
+	Body     string `yaml:"body"`
+	MinWords int    `yaml:"min_words"`
+	MaxWords int    `yaml:"max_words"`
+}
+
+// Validate returns an error if the rule has an unknown action, or an invalid pattern.
+func (r *Rule) Validate() error {
+	if r.Action != RuleInclude && r.Action != RuleExclude {
+		return fmt.Errorf("action must be %q or %q, got %q", RuleInclude, RuleExclude, r.Action)
+	}
+	for _, p := range []string{r.Title, r.Section, r.Tag, r.Author, r.Body} {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if r.MinWords < 0 || r.MaxWords < 0 || r.MaxWords > 0 && r.MaxWords < r.MinWords {
+		return fmt.Errorf("invalid word count range %d to %d", r.MinWords, r.MaxWords)
+	}
+	return nil
+}
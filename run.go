@@ -0,0 +1,59 @@
+package paperboy
+
+import (
+	"context"
+	"time"
+)
+
+// Run is the record of a single run of a task. The counts are reported by the task,
+// through the run found in its context.
+type Run struct {
+	Task       string
+	Start      time.Time
+	End        time.Time
+	Duration   time.Duration
+	Triggered  bool
+	Fetched    int
+	Filtered   int
+	Summarized int
+	Stored     int
+	Failed     int
+	Failures   []Failure `json:",omitempty"`
+	Error      string    `json:",omitempty"`
+}
+
+// Failure is an article which could not be ingested during a run, and the stage it failed at.
+type Failure struct {
+	ContentID string
+	Title     string
+	Stage     string
+	Error     string
+}
+
+// Stages of ingestion at which an article may fail.
+const (
+	StageSummarize = "summarize"
+	StageStore     = "store"
+)
+
+// RunService records the runs of tasks.
+//
+//	Record: stores the run.
+//	Runs: returns the last n runs of the task, newest first.
+type RunService interface {
+	Record(ctx context.Context, r *Run) error
+	Runs(ctx context.Context, task string, n int) ([]*Run, error)
+}
+
+type runKey struct{}
+
+// NewRunContext returns a copy of ctx carrying the run, so that the task may report on it.
+func NewRunContext(ctx context.Context, r *Run) context.Context {
+	return context.WithValue(ctx, runKey{}, r)
+}
+
+// RunFromContext returns the run carried by ctx, or nil if there is none.
+func RunFromContext(ctx context.Context) *Run {
+	r, _ := ctx.Value(runKey{}).(*Run)
+	return r
+}
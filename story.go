@@ -0,0 +1,40 @@
+package paperboy
+
+import (
+	"context"
+	"time"
+)
+
+// Story is a group of summaries, from any section or source, which cover the same news.
+// Date is that of its latest summary, and Summary is its representative summary.
+type Story struct {
+	ID             string `bson:"_id"`
+	Size           int
+	Date           time.Time
+	Representative string `json:"-"`
+	Members        []StoryMember
+	Summary        *Summary `bson:"-"`
+}
+
+// StoryMember is a summary in a story, with the signatures it is compared by.
+// SimHash is stored as an int64, as BSON has no unsigned integers.
+type StoryMember struct {
+	ContentID string `json:"ContentId"`
+	SectionID string `json:"SectionId"`
+	Title     string
+	Date      time.Time
+	SimHash   int64    `json:"-"`
+	Keywords  []string `json:"-"`
+}
+
+// StoryService defines the functionality provided by the service.
+//
+//	Stories: returns the stories with at least min summaries, latest first, with their
+//		representative summaries. An empty sectionID matches stories in all sections.
+//	Recent: returns the stories with a summary published between from and to.
+//	SaveStory: writes a story, replacing any story with the same ID.
+type StoryService interface {
+	Stories(ctx context.Context, sectionID string, min, size int) ([]*Story, error)
+	Recent(ctx context.Context, from, to time.Time) ([]*Story, error)
+	SaveStory(ctx context.Context, st *Story) error
+}
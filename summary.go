@@ -1,6 +1,9 @@
 package paperboy
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Info contains meta information about the article such as the contentId,
 // sectionId, sectionName, url, authors, and date of publication.
@@ -43,13 +46,33 @@ type Image struct {
 }
 
 // Summary contains all the relevant information including objectid, metadata,
-// article, data, and image data.
+// article, data, and image data. Hash is the hash of the article's body text,
+// and SimHash its fingerprint, used to find near-duplicates.
 type Summary struct {
 	ObjectID string `json:"ObjectId" bson:"-"`
 	Info     Info
 	Article  Article
 	Image    Image
 	Score    float64 `json:"score,omitempty" bson:"score,omitempty"`
+	Hash     string  `json:"-"`
+	SimHash  uint64  `json:"-" bson:"-"`
+}
+
+// SummarizeOptions configures a single summarization, such as the number of
+// sentences and keywords to extract.
+type SummarizeOptions struct {
+	Sentences int `yaml:"sentences"`
+	Keywords  int `yaml:"keywords"`
+}
+
+// DefaultSummarizeOptions are used for any option left unset.
+var DefaultSummarizeOptions = SummarizeOptions{Sentences: 7, Keywords: 10}
+
+// A Summarizer is a summarization engine, and is independent of any news source.
+//
+//	Summarize: returns the summary sentences, keywords, and lengths of a text.
+type Summarizer interface {
+	Summarize(ctx context.Context, text string, opts SummarizeOptions) (*Article, error)
 }
 
 // SummaryService defines the functionality provided by the service.
@@ -58,9 +81,28 @@ type Summary struct {
 //		from endDate, and with size limit.
 //  Search: returns a list of summaries with keywords matching the query.
 // 	Create: writes a summary to the database.
+//	Hashes: returns the hashes of the summaries with the given contentIDs.
+//	UpdateInfo: writes the metadata of a summary, leaving the summarization as is.
 type SummaryService interface {
-	Summary(objectID string) (*Summary, error)
-	Summaries(sectionID string, endDate time.Time, size int) ([]*Summary, string, error)
-	Search(query string, size int) ([]*Summary, error)
-	Create(s *Summary) error
+	Summary(ctx context.Context, objectID string) (*Summary, error)
+	Summaries(ctx context.Context, sectionID string, endDate time.Time, size int) ([]*Summary, string, error)
+	Search(ctx context.Context, query string, size int) ([]*Summary, error)
+	Create(ctx context.Context, s *Summary) error
+	Hashes(ctx context.Context, contentIDs []string) (map[string]string, error)
+	UpdateInfo(ctx context.Context, s *Summary) error
+}
+
+// Revision is a prior version of a summary, kept when the article was updated.
+// Revised is the time at which the summary was replaced.
+type Revision struct {
+	ContentID string `json:"ContentId"`
+	Revised   time.Time
+	Summary   Summary
+}
+
+// RevisionService defines the functionality provided by the service.
+//
+//	Revisions: returns the prior versions of the summary with a given objectID, newest first.
+type RevisionService interface {
+	Revisions(ctx context.Context, objectID string) ([]*Revision, error)
 }
@@ -1,12 +1,16 @@
 package paperboy
 
-import "time"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 // Parameter represents a single function parameter.
 type Parameter interface{}
 
-// Task represents pointer to the scheduled function. It must return an error.
-type Task interface{}
+// Task represents the scheduled function, it is given the Tasker's context.
+type Task func(ctx context.Context) error
 
 // TaskConfig holds the configuration for a specified task, such as the name, period,
 // and other configurations.
@@ -14,19 +18,110 @@ type TaskConfig struct {
 	Name   string
 	Period time.Duration
 
+	// Schedule are cron expressions such as '*/15 7-21 * * *', used instead of Period if set.
+	// The task runs at the earliest time matched by any expression, in Location (defaults to UTC).
+	Schedule []string
+	Location *time.Location
+
 	Recover       bool
-	RecoverPeriod time.Duration
-	RecoverStep   time.Duration // Not yet implemented.
+	RecoverPeriod time.Duration // The delay before retrying after the first failure, must be positive.
+	Backoff       Backoff
+}
+
+// Backoff configures the delays between retries of a failing task. The n-th retry waits
+// RecoverPeriod * Multiplier^(n-1), capped at Max, and varied randomly by up to ±Jitter.
+// After MaxAttempts retries, the circuit opens and the task only runs as scheduled,
+// until a run succeeds. Zero values retry every RecoverPeriod, indefinitely.
+type Backoff struct {
+	Multiplier  float64       `yaml:"multiplier"`
+	Max         time.Duration `yaml:"max"`
+	Jitter      float64       `yaml:"jitter"`
+	MaxAttempts int           `yaml:"max_attempts"`
 }
 
-// A TaskerFactory is responsible for creating tasks.
+// CircuitState describes whether a Tasker is retrying a failing task.
+type CircuitState string
+
+// States of the circuit of a Tasker.
+//
+//	CircuitClosed: the last run succeeded.
+//	CircuitRecovering: runs are failing, and being retried with backoff.
+//	CircuitOpen: retries are exhausted, and the task only runs as scheduled.
+const (
+	CircuitClosed     CircuitState = "closed"
+	CircuitRecovering CircuitState = "recovering"
+	CircuitOpen       CircuitState = "open"
+)
+
+// A TaskerFactory is responsible for creating tasks, and keeps track of the Taskers it created.
+//
+//	CreateTasker: returns a Tasker for the task, named after conf.Name.
+//	Tasker: returns the Tasker with the given name.
+//	Taskers: returns every Tasker created, in order of creation.
 type TaskerFactory interface {
-	CreateTasker(conf TaskConfig, task Task, params ...Parameter) (Tasker, error)
+	CreateTasker(conf TaskConfig, task Task) (Tasker, error)
+	Tasker(name string) (Tasker, error)
+	Taskers() []Tasker
 }
 
+// TaskState describes what a Tasker is doing.
+type TaskState string
+
+// States of a Tasker. A paused Tasker skips its scheduled runs, but may still be triggered.
+const (
+	TaskIdle    TaskState = "idle"
+	TaskRunning TaskState = "running"
+	TaskPaused  TaskState = "paused"
+	TaskStopped TaskState = "stopped"
+)
+
+// Errors returned when controlling a Tasker.
+//
+//	ErrTaskerStopped: the Tasker was stopped, or never started.
+//	ErrNotLeader: the task is led by another replica, which is the one to control.
+var (
+	ErrTaskerStopped = errors.New("tasker is stopped")
+	ErrNotLeader     = errors.New("task is led by another replica")
+)
+
 // A Tasker corresponds to a task, and is responsible for the execution.
 // Execution begins in a seperate goroutine, and is triggered periodically
-// as configured.
+// as configured, until ctx is cancelled or the Tasker is stopped.
+//
+//	Name: returns the name of the task.
+//	State: returns the current state of the Tasker.
+//	Circuit: returns whether the Tasker is retrying a failing task.
+//	Leading: returns whether the task runs on this replica, rather than another.
+//	Next: returns the time of the next scheduled run, or the zero time if stopped or not leading.
+//	Start: begins executing the task, runs are given ctx. Only the first call starts it.
+//	Stop: stops executing the task for good, waiting for a run in progress until ctx is done.
+//	Pause: skips scheduled runs until resumed.
+//	Resume: resumes scheduled runs, the schedule itself is unaffected by pausing.
+//	Trigger: runs the task as soon as possible, without affecting the schedule.
+//
+// Pause, Resume, and Trigger return ErrNotLeader on a replica which is not leading the task.
 type Tasker interface {
-	Start()
+	Name() string
+	State() TaskState
+	Circuit() CircuitState
+	Leading() bool
+	Next() time.Time
+	Start(ctx context.Context)
+	Stop(ctx context.Context) error
+	Pause() error
+	Resume() error
+	Trigger() error
+}
+
+// A Locker grants leases on named locks, so that a task runs on one replica at a time.
+// A lease expires after its ttl unless renewed, so that a replica which has died
+// does not hold the lock forever.
+//
+//	Acquire: takes the lock for ttl, returning false if it is held by another replica.
+//	Renew: extends the lease on a lock held by this replica, returning false if it was lost.
+//	Release: releases a lock held by this replica.
+type Locker interface {
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+	Renew(ctx context.Context, name string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name string) error
 }
@@ -0,0 +1,36 @@
+package tasker
+
+import (
+	"math"
+	"math/rand"
+	"paperboy-back"
+	"sync"
+	"time"
+)
+
+// Source of the jitter, seeded so that taskers on different hosts do not retry in lockstep.
+var (
+	randMu sync.Mutex
+	random = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// delay returns the time to wait before the given retry, counting from 1.
+func delay(conf *paperboy.TaskConfig, retry int) time.Duration {
+	b := conf.Backoff
+
+	d := float64(conf.RecoverPeriod)
+	if b.Multiplier > 1 {
+		d *= math.Pow(b.Multiplier, float64(retry-1))
+	}
+	if b.Max > 0 {
+		d = math.Min(d, float64(b.Max))
+	}
+	d = math.Min(d, math.MaxInt64/2)
+
+	if b.Jitter > 0 {
+		randMu.Lock()
+		d *= 1 + b.Jitter*(2*random.Float64()-1)
+		randMu.Unlock()
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,124 @@
+package tasker
+
+import (
+	"context"
+	"errors"
+	"paperboy-back"
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff paperboy.Backoff
+		want    []time.Duration
+	}{
+		{"constant", paperboy.Backoff{}, []time.Duration{1, 1, 1, 1}},
+		{"multiplier", paperboy.Backoff{Multiplier: 2}, []time.Duration{1, 2, 4, 8}},
+		{"multiplier of 1", paperboy.Backoff{Multiplier: 1}, []time.Duration{1, 1, 1, 1}},
+		{"max", paperboy.Backoff{Multiplier: 3, Max: 5 * time.Second}, []time.Duration{1, 3, 5, 5}},
+		{"max without multiplier", paperboy.Backoff{Max: 5 * time.Second}, []time.Duration{1, 1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &paperboy.TaskConfig{RecoverPeriod: time.Second, Backoff: tt.backoff}
+			for i, want := range tt.want {
+				if got := delay(conf, i+1); got != want*time.Second {
+					t.Errorf("retry %d: got %v, want %v", i+1, got, want*time.Second)
+				}
+			}
+		})
+	}
+}
+
+func TestDelayUncapped(t *testing.T) {
+	// Without a max, the delay keeps growing, without overflowing.
+	conf := &paperboy.TaskConfig{RecoverPeriod: time.Second, Backoff: paperboy.Backoff{Multiplier: 2}}
+	prev := time.Duration(0)
+	for retry := 1; retry <= 100; retry++ {
+		d := delay(conf, retry)
+		if d < prev {
+			t.Fatalf("retry %d: got %v, less than %v", retry, d, prev)
+		}
+		prev = d
+	}
+}
+
+func TestDelayJitter(t *testing.T) {
+	conf := &paperboy.TaskConfig{
+		RecoverPeriod: time.Second,
+		Backoff:       paperboy.Backoff{Multiplier: 2, Max: 4 * time.Second, Jitter: 0.25},
+	}
+
+	for retry, base := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 5: 4 * time.Second} {
+		min, max := base*3/4, base*5/4
+		varied := false
+		for i := 0; i < 1000; i++ {
+			d := delay(conf, retry)
+			if d < min || d > max {
+				t.Fatalf("retry %d: got %v, want between %v and %v", retry, d, min, max)
+			}
+			varied = varied || d != base
+		}
+		if !varied {
+			t.Errorf("retry %d: delay was never varied", retry)
+		}
+	}
+}
+
+func TestTaskerCircuit(t *testing.T) {
+	errRun := errors.New("run failed")
+	states := make(chan paperboy.CircuitState)
+
+	// The task fails until the circuit opens, and then recovers. Each run reports the state of
+	// the circuit after the previous one.
+	var tk paperboy.Tasker
+	task := func(ctx context.Context) error {
+		states <- tk.Circuit()
+		if tk.Circuit() == paperboy.CircuitOpen {
+			return nil
+		}
+		return errRun
+	}
+
+	f := &Factory{}
+	tk, err := f.CreateTasker(paperboy.TaskConfig{
+		Name:          "job",
+		Period:        50 * time.Millisecond,
+		RecoverPeriod: time.Millisecond,
+		Backoff:       paperboy.Backoff{MaxAttempts: 2},
+	}, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tk.Start(context.Background())
+	defer tk.Stop(context.Background())
+
+	// The first run fails, and is retried twice before the circuit opens, then the next
+	// scheduled run succeeds and closes it.
+	for i, want := range []paperboy.CircuitState{
+		paperboy.CircuitClosed,
+		paperboy.CircuitRecovering,
+		paperboy.CircuitRecovering,
+		paperboy.CircuitOpen,
+		paperboy.CircuitClosed,
+	} {
+		select {
+		case got := <-states:
+			if got != want {
+				t.Fatalf("run %d: got circuit %s, want %s", i+1, got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("run %d: did not run", i+1)
+		}
+	}
+}
+
+func TestCreateTaskerRecoverPeriod(t *testing.T) {
+	f := &Factory{}
+	task := func(ctx context.Context) error { return nil }
+	if _, err := f.CreateTasker(paperboy.TaskConfig{Name: "job", Period: time.Hour}, task); err == nil {
+		t.Fatal("expected an error without a recover period")
+	}
+}
@@ -0,0 +1,67 @@
+package tasker
+
+import (
+	"context"
+	"fmt"
+	"paperboy-back"
+	"reflect"
+	"runtime"
+)
+
+var (
+	contextInterface = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorInterface   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Func adapts a function taking arbitrary parameters into a paperboy.Task, for tasks written
+// before Task was typed. The function must return a single error, and if its first parameter
+// is a context.Context, it is given the run's context. The remaining parameters are given
+// params, and are checked against the function's signature once, here, rather than on each run.
+func Func(fn interface{}, params ...paperboy.Parameter) (paperboy.Task, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("provided task is not a function")
+	}
+	name := runtime.FuncForPC(v.Pointer()).Name()
+
+	typ := v.Type()
+	if typ.NumOut() != 1 || typ.Out(0) != errorInterface {
+		return nil, fmt.Errorf("function %s must return only an error", name)
+	}
+
+	withContext := typ.NumIn() > 0 && typ.In(0) == contextInterface
+	offset := 0
+	if withContext {
+		offset = 1
+	}
+	if typ.IsVariadic() || typ.NumIn()-offset != len(params) {
+		return nil, fmt.Errorf("function %s takes %d parameters, given %d", name, typ.NumIn()-offset, len(params))
+	}
+
+	args := make([]reflect.Value, typ.NumIn())
+	for i, p := range params {
+		in := typ.In(i + offset)
+		if p == nil {
+			switch in.Kind() {
+			case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+				args[i+offset] = reflect.Zero(in)
+				continue
+			}
+			return nil, fmt.Errorf("function %s parameter %d is %s, given nil", name, i+offset, in)
+		}
+		pv := reflect.ValueOf(p)
+		if !pv.Type().AssignableTo(in) {
+			return nil, fmt.Errorf("function %s parameter %d is %s, given %s", name, i+offset, in, pv.Type())
+		}
+		args[i+offset] = pv
+	}
+
+	return func(ctx context.Context) error {
+		in := args
+		if withContext {
+			in = append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, args[1:]...)
+		}
+		err, _ := v.Call(in)[0].Interface().(error)
+		return err
+	}, nil
+}
@@ -0,0 +1,85 @@
+package tasker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"paperboy-back"
+	"strings"
+	"testing"
+)
+
+func TestFunc(t *testing.T) {
+	errRun := errors.New("run failed")
+	type key struct{}
+
+	tests := []struct {
+		name    string
+		fn      interface{}
+		params  []paperboy.Parameter
+		invalid bool
+		want    error
+	}{
+		{"no parameters", func() error { return errRun }, nil, false, errRun},
+		{"context only", func(ctx context.Context) error {
+			if ctx.Value(key{}) != "run" {
+				return errors.New("not given the run's context")
+			}
+			return nil
+		}, nil, false, nil},
+		{"context and parameters", func(ctx context.Context, s string, n int) error {
+			if ctx.Value(key{}) != "run" || s != "a" || n != 1 {
+				return fmt.Errorf("given %v, %q, %d", ctx.Value(key{}), s, n)
+			}
+			return nil
+		}, []paperboy.Parameter{"a", 1}, false, nil},
+		{"context not first", func(s string, ctx context.Context) error { return nil },
+			[]paperboy.Parameter{"a", context.Background()}, false, nil},
+		{"too few parameters", func(s string, n int) error { return nil }, []paperboy.Parameter{"a"}, true, nil},
+		{"too many parameters", func(ctx context.Context) error { return nil }, []paperboy.Parameter{"a"}, true, nil},
+		{"nil pointer", func(p *int) error {
+			if p != nil {
+				return errors.New("not given nil")
+			}
+			return nil
+		}, []paperboy.Parameter{nil}, false, nil},
+		{"nil interface", func(r io.Reader) error {
+			if r != nil {
+				return errors.New("not given nil")
+			}
+			return nil
+		}, []paperboy.Parameter{nil}, false, nil},
+		{"nil value", func(n int) error { return nil }, []paperboy.Parameter{nil}, true, nil},
+		{"assignable to interface", func(r io.Reader) error { return nil },
+			[]paperboy.Parameter{strings.NewReader("a")}, false, nil},
+		{"not assignable", func(n int) error { return nil }, []paperboy.Parameter{"a"}, true, nil},
+		{"not assignable to interface", func(r io.Reader) error { return nil }, []paperboy.Parameter{1}, true, nil},
+		{"variadic", func(s ...string) error { return nil }, []paperboy.Parameter{"a"}, true, nil},
+		{"variadic without parameters", func(s ...string) error { return nil }, nil, true, nil},
+		{"no return value", func() {}, nil, true, nil},
+		{"non-error return value", func() string { return "" }, nil, true, nil},
+		{"multiple return values", func() (int, error) { return 0, nil }, nil, true, nil},
+		{"not a function", "task", nil, true, nil},
+		{"nil function", (func() error)(nil), nil, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := Func(tt.fn, tt.params...)
+			if tt.invalid {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.WithValue(context.Background(), key{}, "run")
+			if err := task(ctx); err != tt.want {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
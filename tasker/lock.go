@@ -0,0 +1,172 @@
+package tasker
+
+import (
+	"context"
+	"log"
+	"paperboy-back"
+	"sync"
+	"time"
+)
+
+// exec runs the task if the Tasker leads it, or has no Locker. It returns false if the task
+// is led by another replica, and the run was skipped. The run is cancelled if the leadership
+// is lost, as another replica may take over.
+func (t *Tasker) exec(ctx context.Context) (bool, error) {
+	if t.locker == nil {
+		return true, t.call(ctx)
+	}
+
+	t.mu.Lock()
+	leading := t.leading
+	t.mu.Unlock()
+	if leading == nil || leading.Err() != nil {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-leading.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return true, t.call(ctx)
+}
+
+// lead holds the lease on the task's lock until ctx is done, acquiring it whenever it is free,
+// and renewing it while held, runs or not. Only the leader runs the task, so that replicas
+// whose schedules are out of phase do not each run it every period. elected is closed after
+// the first attempt to acquire the lease.
+func (t *Tasker) lead(ctx context.Context, elected chan<- struct{}) {
+	name := "task:" + t.Config.Name
+	ticker := time.NewTicker(t.lease / 3)
+	defer ticker.Stop()
+
+	var cancel context.CancelFunc
+	var renewed time.Time
+	defer func() {
+		if cancel == nil {
+			return
+		}
+		cancel()
+
+		// ctx is done by now, so the lock is released with another.
+		rctx, rcancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer rcancel()
+		if err := t.locker.Release(rctx, name); err != nil {
+			log.Printf("[%s] unable to release lock: %v\n", t.Config.Name, err)
+		}
+	}()
+
+	for {
+		if cancel == nil {
+			ok, err := t.locker.Acquire(ctx, name, t.lease)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				log.Printf("[%s] unable to acquire lock: %v\n", t.Config.Name, err)
+			case ok:
+				log.Printf("[%s] leading task\n", t.Config.Name)
+				leading, resign := context.WithCancel(ctx)
+				t.setLeading(leading)
+				cancel = resign
+				renewed = time.Now()
+			}
+		} else {
+			ok, err := t.locker.Renew(ctx, name, t.lease)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil && time.Since(renewed) < t.lease:
+				log.Printf("[%s] unable to renew lock, retrying: %v\n", t.Config.Name, err)
+			case err != nil || !ok:
+				log.Printf("[%s] lost lock, cancelling run\n", t.Config.Name)
+				cancel()
+				cancel = nil
+				t.setLeading(nil)
+			default:
+				renewed = time.Now()
+			}
+		}
+
+		if elected != nil {
+			close(elected)
+			elected = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tasker) setLeading(leading context.Context) {
+	t.mu.Lock()
+	t.leading = leading
+	t.mu.Unlock()
+}
+
+// Locks is an in-memory store of leases, for replicas within a single process, such as in
+// tests or when running without a shared store. The zero value is ready to use.
+type Locks struct {
+	mu     sync.Mutex
+	leases map[string]lease
+}
+
+type lease struct {
+	owner   string
+	expires time.Time
+}
+
+// Locker returns a paperboy.Locker that holds leases on behalf of the given replica.
+func (l *Locks) Locker(replica string) paperboy.Locker {
+	return &locker{locks: l, owner: replica}
+}
+
+// locker is an implementation of paperboy.Locker backed by Locks.
+type locker struct {
+	locks *Locks
+	owner string
+}
+
+func (l *locker) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+
+	if l.locks.leases == nil {
+		l.locks.leases = make(map[string]lease)
+	}
+	if cur, ok := l.locks.leases[name]; ok && time.Now().Before(cur.expires) {
+		return false, nil
+	}
+	l.locks.leases[name] = lease{owner: l.owner, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (l *locker) Renew(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+
+	cur, ok := l.locks.leases[name]
+	if !ok || cur.owner != l.owner || time.Now().After(cur.expires) {
+		return false, nil
+	}
+	l.locks.leases[name] = lease{owner: l.owner, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (l *locker) Release(ctx context.Context, name string) error {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+
+	if cur, ok := l.locks.leases[name]; ok && cur.owner == l.owner {
+		delete(l.locks.leases, name)
+	}
+	return nil
+}
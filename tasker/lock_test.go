@@ -0,0 +1,254 @@
+package tasker
+
+import (
+	"context"
+	"errors"
+	"paperboy-back"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLocksRefusesWhileHeld(t *testing.T) {
+	var l Locks
+	a, b := l.Locker("a"), l.Locker("b")
+	ctx := context.Background()
+
+	if ok, _ := a.Acquire(ctx, "task", time.Minute); !ok {
+		t.Fatal("a: expected to acquire free lock")
+	}
+	if ok, _ := b.Acquire(ctx, "task", time.Minute); ok {
+		t.Fatal("b: acquired lock held by a")
+	}
+	if ok, _ := b.Renew(ctx, "task", time.Minute); ok {
+		t.Fatal("b: renewed lock held by a")
+	}
+	if ok, _ := a.Renew(ctx, "task", time.Minute); !ok {
+		t.Fatal("a: expected to renew own lock")
+	}
+}
+
+func TestLocksTakeoverAfterExpiry(t *testing.T) {
+	var l Locks
+	a, b := l.Locker("a"), l.Locker("b")
+	ctx := context.Background()
+
+	if ok, _ := a.Acquire(ctx, "task", 20*time.Millisecond); !ok {
+		t.Fatal("a: expected to acquire free lock")
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	if ok, _ := b.Acquire(ctx, "task", time.Minute); !ok {
+		t.Fatal("b: expected to acquire expired lock")
+	}
+	if ok, _ := a.Renew(ctx, "task", time.Minute); ok {
+		t.Fatal("a: renewed lock taken over by b")
+	}
+}
+
+func TestLocksReleaseOnlyOwner(t *testing.T) {
+	var l Locks
+	a, b := l.Locker("a"), l.Locker("b")
+	ctx := context.Background()
+
+	a.Acquire(ctx, "task", time.Minute)
+	if err := b.Release(ctx, "task"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := b.Acquire(ctx, "task", time.Minute); ok {
+		t.Fatal("b: released lock held by a")
+	}
+
+	if err := a.Release(ctx, "task"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := b.Acquire(ctx, "task", time.Minute); !ok {
+		t.Fatal("b: expected to acquire lock released by a")
+	}
+}
+
+// start returns a started Tasker of a factory with the given locker, stopped with the test.
+// The lease is long enough for renewals not to lapse on a busy machine.
+func start(t *testing.T, lk paperboy.Locker, period time.Duration, task paperboy.Task) paperboy.Tasker {
+	t.Helper()
+	f := &Factory{Locker: lk, Lease: 300 * time.Millisecond}
+	tk, err := f.CreateTasker(paperboy.TaskConfig{Name: "job", Period: period, RecoverPeriod: period}, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tk.Start(context.Background())
+	t.Cleanup(func() {
+		// Cancels a run in progress at once, which may be waiting on the test.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		tk.Stop(ctx)
+	})
+	return tk
+}
+
+// report returns a task which sends name on runs at every run.
+func report(runs chan<- string, name string) paperboy.Task {
+	return func(ctx context.Context) error {
+		select {
+		case runs <- name:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+}
+
+// next returns the name sent by the next run.
+func next(t *testing.T, runs <-chan string) string {
+	t.Helper()
+	select {
+	case name := <-runs:
+		return name
+	case <-time.After(2 * time.Second):
+		t.Fatal("task did not run")
+		return ""
+	}
+}
+
+func TestTaskerRunsOnceAcrossReplicas(t *testing.T) {
+	var l Locks
+	runs := make(chan string)
+
+	// b starts once a leads, out of phase, as in a rolling deploy.
+	start(t, l.Locker("a"), 10*time.Millisecond, report(runs, "a"))
+	if name := next(t, runs); name != "a" {
+		t.Fatalf("got first run by %s, want a", name)
+	}
+	start(t, l.Locker("b"), 10*time.Millisecond, report(runs, "b"))
+
+	for i := 0; i < 10; i++ {
+		if name := next(t, runs); name != "a" {
+			t.Fatalf("run %d: got run by %s while a leads", i+1, name)
+		}
+	}
+}
+
+func TestTaskerTakeoverAfterStop(t *testing.T) {
+	var l Locks
+	runs := make(chan string)
+
+	ta := start(t, l.Locker("a"), 10*time.Millisecond, report(runs, "a"))
+	if name := next(t, runs); name != "a" {
+		t.Fatalf("got first run by %s, want a", name)
+	}
+	start(t, l.Locker("b"), 10*time.Millisecond, report(runs, "b"))
+	for i := 0; i < 3; i++ {
+		if name := next(t, runs); name != "a" {
+			t.Fatalf("run %d: got run by %s while a leads", i+1, name)
+		}
+	}
+
+	// a gives up the lock once stopped, a run of a may still be reported while it stops.
+	stopped := make(chan struct{})
+	go func() {
+		ta.Stop(context.Background())
+		close(stopped)
+	}()
+	for {
+		done := false
+		select {
+		case <-stopped:
+			done = true
+		default:
+		}
+		if name := next(t, runs); name == "b" {
+			break
+		} else if done {
+			t.Fatal("a ran after it was stopped")
+		}
+	}
+}
+
+// lossy is a paperboy.Locker whose lock is lost once lose is called.
+type lossy struct {
+	mu   sync.Mutex
+	lost bool
+}
+
+func (l *lossy) lose() {
+	l.mu.Lock()
+	l.lost = true
+	l.mu.Unlock()
+}
+
+func (l *lossy) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.lost, nil
+}
+
+func (l *lossy) Renew(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	return l.Acquire(ctx, name, ttl)
+}
+
+func (l *lossy) Release(ctx context.Context, name string) error {
+	return nil
+}
+
+func TestTaskerCancelsRunOnLostLock(t *testing.T) {
+	lk := &lossy{}
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	start(t, lk, time.Hour, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("run did not start")
+	}
+	lk.lose()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("run was not cancelled after the lock was lost")
+	}
+}
+
+func TestTaskerControlOnlyOnLeader(t *testing.T) {
+	var l Locks
+	ran := make(chan struct{}, 1)
+	leader := start(t, l.Locker("a"), time.Hour, func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	})
+	<-ran
+	follower := start(t, l.Locker("b"), time.Hour, func(ctx context.Context) error {
+		t.Error("follower ran the task")
+		return nil
+	})
+
+	if !leader.Leading() || follower.Leading() {
+		t.Fatalf("leading: got leader %v, follower %v", leader.Leading(), follower.Leading())
+	}
+	if !follower.Next().IsZero() {
+		t.Errorf("follower: got next run %v, want none", follower.Next())
+	}
+	for name, fn := range map[string]func() error{
+		"pause": follower.Pause, "resume": follower.Resume, "trigger": follower.Trigger,
+	} {
+		if err := fn(); !errors.Is(err, paperboy.ErrNotLeader) {
+			t.Errorf("follower %s: got %v, want ErrNotLeader", name, err)
+		}
+	}
+
+	if err := leader.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.Trigger(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("leader did not run the triggered task")
+	}
+}
@@ -0,0 +1,61 @@
+package tasker
+
+import (
+	"fmt"
+	"paperboy-back"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// schedule returns the time of the next run after t.
+type schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// period schedules runs at a fixed interval.
+type period time.Duration
+
+func (p period) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(p))
+}
+
+// crontab schedules runs at the earliest time matched by any of its cron schedules.
+type crontab struct {
+	schedules []cron.Schedule
+	loc       *time.Location
+}
+
+func (c *crontab) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, s := range c.schedules {
+		if n := s.Next(t.In(c.loc)); next.IsZero() || n.Before(next) {
+			next = n
+		}
+	}
+	return next
+}
+
+// newSchedule returns the schedule described by the configuration, using the cron
+// expressions if any are given, and the period otherwise.
+func newSchedule(conf *paperboy.TaskConfig) (schedule, error) {
+	if len(conf.Schedule) == 0 {
+		if conf.Period <= 0 {
+			return nil, fmt.Errorf("period must be positive, got %v", conf.Period)
+		}
+		return period(conf.Period), nil
+	}
+
+	c := &crontab{loc: conf.Location}
+	if c.loc == nil {
+		c.loc = time.UTC
+	}
+	for _, expr := range conf.Schedule {
+		s, err := cron.ParseStandard(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		c.schedules = append(c.schedules, s)
+	}
+	return c, nil
+}
@@ -1,16 +1,32 @@
 package tasker
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"paperboy-back"
-	"reflect"
-	"runtime"
+	"runtime/debug"
+	"sync"
 	"time"
 )
 
-// Factory is responsible for creating Taskers assigned with tasks.
-type Factory struct{}
+// Factory is responsible for creating Taskers assigned with tasks,
+// and keeps track of the Taskers it created.
+type Factory struct {
+	// Locker, if set, is used by the Taskers to run each task on one replica, the leader.
+	// The lock is leased for Lease, defaults to DefaultLease, and renewed while held.
+	Locker paperboy.Locker
+	Lease  time.Duration
+
+	// RunService, if set, records every run of the Taskers.
+	RunService paperboy.RunService
+
+	mu      sync.Mutex
+	taskers []*Tasker
+}
+
+// DefaultLease is the default duration of the lease on a task's lock.
+const DefaultLease = 30 * time.Second
 
 var _ paperboy.TaskerFactory = (*Factory)(nil)
 
@@ -19,58 +35,430 @@ var _ paperboy.TaskerFactory = (*Factory)(nil)
 type Tasker struct {
 	Config *paperboy.TaskConfig
 	Task   paperboy.Task
-	Params *[]paperboy.Parameter
+
+	schedule schedule
+	locker   paperboy.Locker
+	lease    time.Duration
+	runs     paperboy.RunService
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	paused  bool
+	running bool
+	next    time.Time
+	circuit paperboy.CircuitState
+	leading context.Context
+
+	trigger chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+var _ paperboy.Tasker = (*Tasker)(nil)
+
+// CreateTasker returns a Tasker with the given configuration and task.
+// Names must be unique, so that the Tasker may be looked up later.
+func (f *Factory) CreateTasker(conf paperboy.TaskConfig, task paperboy.Task) (paperboy.Tasker, error) {
+	if task == nil {
+		return &Tasker{}, fmt.Errorf("[%s] failed to create tasker: provided task is nil", conf.Name)
+	}
+
+	sched, err := newSchedule(&conf)
+	if err != nil {
+		return &Tasker{}, fmt.Errorf("[%s] failed to create tasker: %w", conf.Name, err)
+	}
+	// Failed runs are retried after RecoverPeriod, without which they would be retried at once.
+	if conf.RecoverPeriod <= 0 {
+		return &Tasker{}, fmt.Errorf("[%s] failed to create tasker: recover period must be positive, got %v", conf.Name, conf.RecoverPeriod)
+	}
+
+	lease := f.Lease
+	if lease == 0 {
+		lease = DefaultLease
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.taskers {
+		if t.Config.Name == conf.Name {
+			return &Tasker{}, fmt.Errorf("[%s] failed to create tasker: name is already in use", conf.Name)
+		}
+	}
+
+	t := &Tasker{
+		Config:   &conf,
+		Task:     task,
+		schedule: sched,
+		locker:   f.Locker,
+		lease:    lease,
+		runs:     f.RunService,
+		trigger:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	f.taskers = append(f.taskers, t)
+
+	return t, nil
+}
+
+// Tasker returns the Tasker with the given name.
+func (f *Factory) Tasker(name string) (paperboy.Tasker, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, t := range f.taskers {
+		if t.Config.Name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("tasker %q not found", name)
 }
 
-// CreateTasker returns a Tasker with the given configuration, task, and function parameters.
-func (f *Factory) CreateTasker(conf paperboy.TaskConfig, task paperboy.Task, params ...paperboy.Parameter) (paperboy.Tasker, error) {
-	taskValue := reflect.ValueOf(task)
-	if taskValue.Kind() != reflect.Func {
-		return &Tasker{}, fmt.Errorf("[%s] failed to create tasker: provided task is not a function", conf.Name)
+// Taskers returns every Tasker created by the factory, in order of creation.
+func (f *Factory) Taskers() []paperboy.Tasker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	taskers := make([]paperboy.Tasker, len(f.taskers))
+	for i, t := range f.taskers {
+		taskers[i] = t
+	}
+	return taskers
+}
+
+// Name returns the name of the task.
+func (t *Tasker) Name() string {
+	return t.Config.Name
+}
+
+// State returns the current state of the Tasker.
+func (t *Tasker) State() paperboy.TaskState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case t.cancel == nil || t.stopped():
+		return paperboy.TaskStopped
+	case t.running:
+		return paperboy.TaskRunning
+	case t.paused:
+		return paperboy.TaskPaused
 	}
+	return paperboy.TaskIdle
+}
 
-	errorInterface := reflect.TypeOf((*error)(nil)).Elem()
-	taskReturn := taskValue.Type().Out(0)
-	if !taskReturn.Implements(errorInterface) {
-		return &Tasker{}, fmt.Errorf("[%s] failed to create tasker: function %s does not return error", conf.Name,
-			runtime.FuncForPC(taskValue.Pointer()).Name())
+// Next returns the time of the next scheduled run, or the zero time if stopped, or if
+// the task is led by another replica, as this replica skips its runs.
+func (t *Tasker) Next() time.Time {
+	if t.State() == paperboy.TaskStopped || !t.Leading() {
+		return time.Time{}
 	}
 
-	return &Tasker{Config: &conf, Task: task, Params: &params}, nil
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.next
+}
+
+// stopped returns whether the Tasker was stopped, or its context is done.
+func (t *Tasker) stopped() bool {
+	select {
+	case <-t.stop:
+		return true
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
 }
 
-// Start begins executing the task at assigned intervals.
-func (t *Tasker) Start() {
+// Start begins executing the task as scheduled, until ctx is cancelled.
+// Each run is given ctx. A Tasker is started once, a stopped Tasker is not restarted.
+func (t *Tasker) Start(ctx context.Context) {
+	t.mu.Lock()
+	if t.cancel != nil || t.stopped() {
+		t.mu.Unlock()
+		log.Printf("[%v] task was already started or stopped, not starting\n", t.Config.Name)
+		return
+	}
 	log.Printf("[%v] starting task...\n", t.Config.Name)
+	ctx, t.cancel = context.WithCancel(ctx)
+	t.mu.Unlock()
 
 	// Executes the task in a separate goroutine.
 	go func() {
-		recovering := false
-		ticker := time.NewTicker(t.Config.Period)
-		defer ticker.Stop()
-
-		// Process the parameters.
-		task := reflect.ValueOf(t.Task)
-		params := make([]reflect.Value, len(*t.Params))
-		for idx, param := range *t.Params {
-			params[idx] = reflect.ValueOf(param)
+		defer close(t.done)
+
+		// Holds the lock while the task is scheduled, if the Tasker has a Locker.
+		if t.locker != nil {
+			lctx, lcancel := context.WithCancel(ctx)
+			elected, led := make(chan struct{}), make(chan struct{})
+			go func() {
+				defer close(led)
+				t.lead(lctx, elected)
+			}()
+			defer func() {
+				lcancel()
+				<-led
+			}()
+			select {
+			case <-elected:
+			case <-ctx.Done():
+			}
 		}
 
-		// Executes task immediately upon startup.
-		for ; true; <-ticker.C {
-			err := task.Call(params)
-			if _, ok := err[0].Interface().(error); ok {
+		failures := 0
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		// schedule sets the timer to fire at the next run.
+		schedule := func(next time.Time) {
+			t.mu.Lock()
+			t.next = next
+			t.mu.Unlock()
+
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(time.Until(next))
+		}
+
+		// Only scheduled runs may change the schedule, triggered runs are run in between.
+		run := func(scheduled bool) {
+			start := time.Now()
+			r := &paperboy.Run{Task: t.Config.Name, Start: start.UTC(), Triggered: !scheduled}
+
+			t.setRunning(true)
+			ran, err := t.exec(paperboy.NewRunContext(ctx, r))
+			t.setRunning(false)
+
+			if !ran {
+				log.Printf("[%s] task is led by another replica, skipping run\n", t.Config.Name)
+				if scheduled {
+					schedule(t.schedule.Next(start))
+				}
+				return
+			}
+			t.record(r, start, err)
+
+			if err != nil {
 				log.Printf("[%s] an error has occured: %s\n", t.Config.Name, err)
-				ticker.Reset(t.Config.RecoverPeriod)
-				recovering = true
-				continue
+				if !scheduled {
+					return
+				}
+
+				// Retry with backoff, until the attempts are exhausted.
+				failures++
+				if max := t.Config.Backoff.MaxAttempts; max > 0 && failures > max {
+					if t.setCircuit(paperboy.CircuitOpen) {
+						log.Printf("[%s] circuit open after %d retries, running as scheduled\n", t.Config.Name, max)
+					}
+					schedule(t.schedule.Next(start))
+					return
+				}
+
+				t.setCircuit(paperboy.CircuitRecovering)
+				d := delay(t.Config, failures)
+				log.Printf("[%s] circuit recovering, retry %d in %v\n", t.Config.Name, failures, d.Round(time.Second))
+				schedule(time.Now().Add(d))
+			} else if scheduled {
+				// If Tasker has recovered from task, return to the normal schedule.
+				if t.setCircuit(paperboy.CircuitClosed) {
+					log.Printf("[%s] circuit closed, task has recovered after %d failures\n", t.Config.Name, failures)
+				}
+				failures = 0
+				schedule(t.schedule.Next(start))
 			}
+		}
 
-			// If Tasker has recovered from task, reset to normal ticker.
-			if recovering {
-				ticker.Reset(t.Config.Period)
-				recovering = false
+		// Executes task immediately upon startup.
+		if !t.isPaused() {
+			run(true)
+		} else {
+			schedule(t.schedule.Next(time.Now()))
+		}
+
+		for {
+			select {
+			case <-timer.C:
+				if t.isPaused() {
+					log.Printf("[%v] task is paused, skipping run\n", t.Config.Name)
+					schedule(t.schedule.Next(time.Now()))
+					continue
+				}
+				run(true)
+			case <-t.trigger:
+				log.Printf("[%v] task triggered\n", t.Config.Name)
+				run(false)
+			case <-t.stop:
+				log.Printf("[%v] stopping task...\n", t.Config.Name)
+				return
+			case <-ctx.Done():
+				log.Printf("[%v] stopping task...\n", t.Config.Name)
+				return
 			}
 		}
 	}()
 }
+
+// record completes the run, and stores it if the Tasker has a RunService.
+func (t *Tasker) record(r *paperboy.Run, start time.Time, err error) {
+	r.End = time.Now().UTC()
+	r.Duration = time.Since(start)
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	if t.runs == nil {
+		return
+	}
+
+	// The Tasker's context may be done, if the run was cancelled.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := t.runs.Record(ctx, r); err != nil {
+		log.Printf("[%s] unable to record run: %v\n", t.Config.Name, err)
+	}
+}
+
+// call runs the task, and recovers from a panic as a failed run, so that a
+// bad run does not bring down the process.
+func (t *Tasker) call(ctx context.Context) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("[%s] task panicked: %v\n%s", t.Config.Name, p, debug.Stack())
+			err = fmt.Errorf("task panicked: %v", p)
+		}
+	}()
+	return t.Task(ctx)
+}
+
+// Circuit returns whether the Tasker is retrying a failing task.
+func (t *Tasker) Circuit() paperboy.CircuitState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.circuit == "" {
+		return paperboy.CircuitClosed
+	}
+	return t.circuit
+}
+
+// setCircuit sets the state of the circuit, and returns whether it has changed.
+func (t *Tasker) setCircuit(state paperboy.CircuitState) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev := t.circuit
+	if prev == "" {
+		prev = paperboy.CircuitClosed
+	}
+	t.circuit = state
+	return prev != state
+}
+
+func (t *Tasker) setRunning(running bool) {
+	t.mu.Lock()
+	t.running = running
+	t.mu.Unlock()
+}
+
+func (t *Tasker) isPaused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.paused
+}
+
+// Leading returns whether this replica holds the task's lock, and so runs the task.
+// A Tasker without a Locker always leads.
+func (t *Tasker) Leading() bool {
+	if t.locker == nil {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.leading != nil && t.leading.Err() == nil
+}
+
+// control returns an error if the Tasker cannot be controlled, as it was stopped, or the
+// task is led by another replica.
+func (t *Tasker) control() error {
+	if t.State() == paperboy.TaskStopped {
+		return paperboy.ErrTaskerStopped
+	}
+	if !t.Leading() {
+		return paperboy.ErrNotLeader
+	}
+	return nil
+}
+
+// Pause skips the scheduled runs of the task until resumed. A run in progress is unaffected.
+// The pause is kept by this replica, so it is lost if another replica takes over the task.
+func (t *Tasker) Pause() error {
+	if err := t.control(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.paused = true
+	t.mu.Unlock()
+	log.Printf("[%v] pausing task...\n", t.Config.Name)
+	return nil
+}
+
+// Resume resumes the scheduled runs of the task.
+func (t *Tasker) Resume() error {
+	if err := t.control(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.paused = false
+	t.mu.Unlock()
+	log.Printf("[%v] resuming task...\n", t.Config.Name)
+	return nil
+}
+
+// Trigger runs the task as soon as the run in progress, if any, has finished. The schedule is
+// unaffected, and triggers received while a triggered run is pending are merged into it.
+func (t *Tasker) Trigger() error {
+	if err := t.control(); err != nil {
+		return err
+	}
+
+	select {
+	case t.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Stop stops scheduling the task, and waits for a run in progress to finish. If ctx is done
+// first, the run's context is cancelled, and Stop returns once the run has returned.
+// A stopped Tasker cannot be started again, and releases its lock to another replica.
+func (t *Tasker) Stop(ctx context.Context) error {
+	t.once.Do(func() { close(t.stop) })
+
+	// The task was never started.
+	t.mu.Lock()
+	cancel := t.cancel
+	t.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	select {
+	case <-t.done:
+		cancel()
+		return nil
+	case <-ctx.Done():
+		log.Printf("[%v] cancelling task...\n", t.Config.Name)
+		cancel()
+		<-t.done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package tasker
+
+import (
+	"context"
+	"paperboy-back"
+	"testing"
+	"time"
+)
+
+func TestTaskerStartTwice(t *testing.T) {
+	runs := make(chan struct{}, 2)
+	f := &Factory{}
+	tk, err := f.CreateTasker(paperboy.TaskConfig{Name: "job", Period: time.Hour, RecoverPeriod: time.Hour}, func(ctx context.Context) error {
+		runs <- struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tk.Start(context.Background())
+	tk.Start(context.Background())
+	<-runs
+	select {
+	case <-runs:
+		t.Fatal("started twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := tk.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := tk.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTaskerStartAfterStop(t *testing.T) {
+	f := &Factory{}
+	tk, err := f.CreateTasker(paperboy.TaskConfig{Name: "job", Period: time.Hour, RecoverPeriod: time.Hour}, func(ctx context.Context) error {
+		t.Error("stopped task ran")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tk.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tk.Start(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	if s := tk.State(); s != paperboy.TaskStopped {
+		t.Fatalf("got state %s, want stopped", s)
+	}
+}
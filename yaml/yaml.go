@@ -0,0 +1,34 @@
+package yaml
+
+import (
+	"fmt"
+	"os"
+	"paperboy-back"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load returns the configuration read from the YAML file at path, with defaults set.
+// Unknown fields and invalid settings are reported as errors.
+func Load(path string) (*paperboy.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to open config", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+
+	var c paperboy.Config
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%q: %w", "unable to decode config "+path, err)
+	}
+
+	c.SetDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}